@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go.starlark.net/syntax"
+)
+
+// runCheck parses a .star file and reports any syntax errors without
+// executing it.
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: starlark-mcp check FILE")
+	}
+	path := fs.Arg(0)
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := syntax.Parse(path, src, 0); err != nil {
+		return err
+	}
+	fmt.Println("OK")
+	return nil
+}
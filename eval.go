@@ -0,0 +1,14 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// runEval points users at the eval harness: the LLM eval suite is run
+// through Inspect AI (see Taskfile.yml's "eval" task), not this binary.
+func runEval(args []string) error {
+	fs := flag.NewFlagSet("eval", flag.ExitOnError)
+	fs.Parse(args)
+	return fmt.Errorf("not run from here; use `task eval` to run the Inspect AI eval suite")
+}
@@ -1,16 +1,57 @@
 package main
 
 import (
-	"context"
-	"log"
-
-	"github.com/alexmdac/starlark-mcp/server"
-	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"fmt"
+	"os"
 )
 
+const usage = `usage: starlark-mcp <command> [arguments]
+
+commands:
+  serve   run the MCP server over stdio (default)
+  run     execute a .star file through the sandbox
+  repl    start an interactive Starlark REPL
+  check   parse a .star file without executing it
+  eval    run the LLM eval suite
+`
+
 func main() {
-	s := server.New()
-	if err := s.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
-		log.Fatal(err)
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "--version" || args[0] == "-version") {
+		printVersion()
+		return
+	}
+
+	cmd := "serve"
+	if len(args) > 0 && !isFlag(args[0]) {
+		cmd = args[0]
+		args = args[1:]
 	}
+
+	var err error
+	switch cmd {
+	case "serve":
+		err = runServe(args)
+	case "run":
+		err = runRun(args)
+	case "repl":
+		err = runRepl(args)
+	case "check":
+		err = runCheck(args)
+	case "eval":
+		err = runEval(args)
+	default:
+		fmt.Fprintf(os.Stderr, "starlark-mcp: unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "starlark-mcp %s: %v\n", cmd, err)
+		os.Exit(1)
+	}
+}
+
+// isFlag reports whether arg looks like a flag (e.g. "-h") rather than a
+// subcommand name, so "starlark-mcp -h" still works without "serve".
+func isFlag(arg string) bool {
+	return len(arg) > 0 && arg[0] == '-'
 }
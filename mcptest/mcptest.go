@@ -0,0 +1,86 @@
+// Package mcptest provides helpers for testing servers built on
+// server.New(), covering the in-memory client/server bootstrap and common
+// CallTool assertions so consumers don't have to copy-paste them.
+package mcptest
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// NewClient connects a new in-memory client to s and returns the client
+// session. The server and client are closed automatically via t.Cleanup.
+func NewClient(t testing.TB, s *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+
+	t1, t2 := mcp.NewInMemoryTransports()
+	client := mcp.NewClient(&mcp.Implementation{Name: "mcptest client"}, nil)
+
+	serverSession, err := s.Connect(t.Context(), t1, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect server: %v", err)
+	}
+	clientSession, err := client.Connect(t.Context(), t2, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := clientSession.Close(); err != nil {
+			t.Fatalf("Failed to close client session: %v", err)
+		}
+		if err := serverSession.Wait(); err != nil {
+			t.Fatalf("Server session failed: %v", err)
+		}
+	})
+
+	return clientSession
+}
+
+// CallTool calls a tool and fails the test if the call itself (as opposed
+// to the tool's result) errors.
+func CallTool(t testing.TB, client *mcp.ClientSession, params *mcp.CallToolParams) *mcp.CallToolResult {
+	t.Helper()
+	res, err := client.CallTool(t.Context(), params)
+	if err != nil {
+		t.Fatalf("client.CallTool failed: %v", err)
+	}
+	return res
+}
+
+// ExpectCallToolSuccess calls a tool, fails the test if it returns an error
+// result, and returns its text content.
+func ExpectCallToolSuccess(t testing.TB, client *mcp.ClientSession, params *mcp.CallToolParams) string {
+	t.Helper()
+	res := CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("Expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	return ExpectTextContent(t, res)
+}
+
+// ExpectCallToolError calls a tool, fails the test if it does not return an
+// error result, and returns its text content.
+func ExpectCallToolError(t testing.TB, client *mcp.ClientSession, params *mcp.CallToolParams) string {
+	t.Helper()
+	res := CallTool(t, client, params)
+	if !res.IsError {
+		t.Fatal("expected an error, but got none")
+	}
+	return ExpectTextContent(t, res)
+}
+
+// ExpectTextContent returns the text of a result's single content block,
+// failing the test if the result doesn't have exactly one text block.
+func ExpectTextContent(t testing.TB, res *mcp.CallToolResult) string {
+	t.Helper()
+	if len(res.Content) != 1 {
+		t.Fatalf("Incorrect number of content blocks:\n- want: 1\n-  got: %d", len(res.Content))
+	}
+	textContent, ok := res.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("Incorrect content block type:\n- want: *mcp.TextContent\n-  got: %T", res.Content[0])
+	}
+	return textContent.Text
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+
+	"github.com/alexmdac/starlark-mcp/server"
+	"go.starlark.net/starlark"
+)
+
+// runRepl starts an interactive Starlark REPL using the same dialect,
+// builtins, and modules as the execute-starlark tool. Globals persist
+// across inputs. This repo has no readline dependency, so line editing
+// relies on the terminal's own line discipline rather than arrow-key
+// history.
+func runRepl(args []string) error {
+	fs := flag.NewFlagSet("repl", flag.ExitOnError)
+	fs.Parse(args)
+
+	thread := server.NewThread(func(_ *starlark.Thread, msg string) {
+		fmt.Println(msg)
+	})
+	globals := make(starlark.StringDict)
+	maps.Copy(globals, server.Predeclared())
+
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Fprintln(os.Stderr, "starlark-mcp repl (Ctrl-D to exit)")
+	for {
+		input, ok := readStatement(scanner)
+		if !ok {
+			fmt.Fprintln(os.Stderr)
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(input)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, ":load "):
+			loadModule(globals, strings.TrimPrefix(line, ":load "))
+			continue
+		}
+
+		newGlobals, err := starlark.ExecFileOptions(server.FileOptions(), thread, "<stdin>", input, globals)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			continue
+		}
+		globals = newGlobals
+	}
+}
+
+// readStatement reads one top-level statement from scanner, buffering
+// continuation lines so that multi-line constructs (def, for, while, if)
+// can be entered interactively. A line ending in ":" opens a block; the
+// block is read until a blank line ends it, the same way most interactive
+// interpreters for indentation-based languages prompt for continuation.
+// The second return value is false at EOF with nothing buffered.
+func readStatement(scanner *bufio.Scanner) (string, bool) {
+	fmt.Fprint(os.Stderr, ">>> ")
+	if !scanner.Scan() {
+		return "", false
+	}
+	lines := []string{scanner.Text()}
+	if !blockOpen(lines[0]) {
+		return lines[0], true
+	}
+
+	for {
+		fmt.Fprint(os.Stderr, "... ")
+		if !scanner.Scan() {
+			break
+		}
+		if strings.TrimSpace(scanner.Text()) == "" {
+			break
+		}
+		lines = append(lines, scanner.Text())
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+// blockOpen reports whether line looks like it opens an indented block
+// (def/for/while/if/else/elif, or anything else ending in ":") that needs
+// at least one more line before it can be executed.
+func blockOpen(line string) bool {
+	return strings.HasSuffix(strings.TrimSpace(line), ":")
+}
+
+// loadModule binds a builtin module's exported members directly into
+// globals, e.g. ":load math" instead of load("math", "sqrt", "pi", ...).
+func loadModule(globals starlark.StringDict, name string) {
+	members, err := server.LoadModule(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return
+	}
+	maps.Copy(globals, members)
+}
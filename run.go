@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alexmdac/starlark-mcp/server"
+)
+
+// runRun executes a .star file through the same engine, prelude, and limits
+// as the execute-starlark tool, and prints its output.
+func runRun(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	timeout := fs.Duration("timeout", 10*time.Second, "execution timeout")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: starlark-mcp run [-timeout DURATION] FILE")
+	}
+
+	src, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	output, err := server.Execute(ctx, string(src))
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/alexmdac/starlark-mcp/server"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// runServe runs the MCP server until the client disconnects (stdio) or the
+// process is killed (sse).
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	transport := fs.String("transport", "stdio", "transport to serve on: stdio or sse")
+	addr := fs.String("addr", "localhost:8080", "bind address for -transport sse")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *transport {
+	case "stdio":
+		s := server.New(version)
+		return s.Run(context.Background(), &mcp.StdioTransport{})
+	case "sse":
+		return runServeSSE(*addr)
+	default:
+		return fmt.Errorf("unknown transport %q (want stdio or sse)", *transport)
+	}
+}
+
+// runServeSSE serves the MCP server over SSE, giving each connecting client
+// its own Server instance. server.New does register package-level state
+// (persistedGlobals, sessionOutputs), but both are keyed by the MCP
+// *ServerSession that produced them, so concurrent SSE clients can't see or
+// evict each other's persisted globals or stored outputs.
+func runServeSSE(addr string) error {
+	handler := mcp.NewSSEHandler(func(*http.Request) *mcp.Server {
+		return server.New(version)
+	})
+	fmt.Printf("starlark-mcp: serving SSE on http://%s\n", addr)
+	return http.ListenAndServe(addr, handler)
+}
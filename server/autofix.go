@@ -0,0 +1,238 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.starlark.net/syntax"
+)
+
+// exponentRe matches the simple case of "x ** y" where both operands are a
+// single identifier, number, or dotted/attribute expression — enough to
+// cover the common cases without risking a bad rewrite of something more
+// complex, where textual substitution could change operator precedence.
+var exponentRe = regexp.MustCompile(`\b([\w.]+)\s*\*\*\s*([\w.]+)\b`)
+
+// autofixFstringRe and fstringPlaceholderRe match the simple case of an f-string
+// containing only {name} placeholders (no expressions or format specs).
+var autofixFstringRe = regexp.MustCompile(`f"([^"{}]*(?:\{\w+\}[^"{}]*)*)"`)
+var fstringPlaceholderRe = regexp.MustCompile(`\{(\w+)\}`)
+
+// powLoadedRe matches a load("math", ...) statement that already imports pow,
+// so autofixProgram doesn't add a second, redundant load line.
+var powLoadedRe = regexp.MustCompile(`load\("math"[^)]*"pow"`)
+
+// rewriteExponents applies exponentRe only to the parts of program that
+// aren't inside a string literal or a comment, so a "x ** y"-shaped
+// substring a user actually wrote inside a string (e.g. an explanatory
+// print) isn't mistaken for an exponent expression and corrupted.
+func rewriteExponents(program string) string {
+	return rewriteOutsideLiterals(program, func(code string) string {
+		return exponentRe.ReplaceAllString(code, "pow($1, $2)")
+	})
+}
+
+// rewriteOutsideLiterals applies rewrite to the runs of program that lie
+// outside string literals and "#" comments, copying literals and comments
+// through unchanged, and reassembles the result in order. It's a lightweight
+// stand-in for tokenizing with the real Starlark scanner, which isn't
+// exported by go.starlark.net/syntax.
+func rewriteOutsideLiterals(program string, rewrite func(code string) string) string {
+	var out, code strings.Builder
+	flush := func() {
+		out.WriteString(rewrite(code.String()))
+		code.Reset()
+	}
+
+	for i := 0; i < len(program); {
+		switch c := program[i]; {
+		case c == '#':
+			flush()
+			if end := strings.IndexByte(program[i:], '\n'); end >= 0 {
+				out.WriteString(program[i : i+end])
+				i += end
+			} else {
+				out.WriteString(program[i:])
+				i = len(program)
+			}
+		case c == '"' || c == '\'':
+			flush()
+			end := stringLiteralEnd(program, i)
+			out.WriteString(program[i:end])
+			i = end
+		default:
+			code.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// stringLiteralEnd returns the index just past the end of the Starlark
+// string literal (single- or triple-quoted) starting at program[start],
+// honoring backslash escapes. If the literal is unterminated, it returns
+// len(program) so the rest of the program is treated as part of it.
+func stringLiteralEnd(program string, start int) int {
+	quote := program[start]
+	delim := string(quote)
+	if strings.HasPrefix(program[start:], delim+delim+delim) {
+		delim = delim + delim + delim
+	}
+
+	i := start + len(delim)
+	for i < len(program) {
+		if program[i] == '\\' && i+1 < len(program) {
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(program[i:], delim) {
+			return i + len(delim)
+		}
+		i++
+	}
+	return len(program)
+}
+
+// autofixProgram mechanically rewrites program to work around a handful of
+// common unsupported constructs, returning the rewritten source and a
+// human-readable list of what changed. It's deliberately conservative: if
+// it can't rewrite a construct with confidence, it leaves it alone for the
+// normal error path (and enrichExecutionError's hints) to report instead.
+func autofixProgram(program string) (rewritten string, changes []string) {
+	rewritten = program
+
+	if fixed := rewriteExponents(rewritten); fixed != rewritten {
+		rewritten = fixed
+		if !powLoadedRe.MatchString(rewritten) {
+			rewritten = "load(\"math\", \"pow\")\n" + rewritten
+		}
+		changes = append(changes, `rewrote "**" to pow(), adding a load("math", "pow")`)
+	}
+
+	if fixed, ok := rewriteFStrings(rewritten); ok {
+		rewritten = fixed
+		changes = append(changes, "rewrote simple f-strings to %-formatting")
+	}
+
+	if needsMainWrap(rewritten) {
+		rewritten = wrapTopLevelInMain(rewritten)
+		changes = append(changes, "wrapped top-level statements in a main() function")
+	}
+
+	return rewritten, changes
+}
+
+// rewriteFStrings replaces f"...{name}..." literals containing only
+// {name} placeholders with "...%s..." % (name, ...).
+func rewriteFStrings(program string) (string, bool) {
+	changed := false
+	rewritten := autofixFstringRe.ReplaceAllStringFunc(program, func(match string) string {
+		body := autofixFstringRe.FindStringSubmatch(match)[1]
+		names := fstringPlaceholderRe.FindAllStringSubmatch(body, -1)
+		if names == nil {
+			return match // a plain string with no placeholders; leave the "f" prefix error as-is
+		}
+		format := fstringPlaceholderRe.ReplaceAllString(body, "%s")
+		args := make([]string, len(names))
+		for i, n := range names {
+			args[i] = n[1]
+		}
+		argsList := strings.Join(args, ", ")
+		if len(args) == 1 {
+			argsList += "," // a single-element tuple requires a trailing comma
+		}
+		changed = true
+		return fmt.Sprintf(`"%s" %% (%s)`, format, argsList)
+	})
+	return rewritten, changed
+}
+
+// needsMainWrap reports whether program has top-level for/if/while
+// statements that the active dialect profile doesn't allow outside a
+// function.
+func needsMainWrap(program string) bool {
+	if fileOptions().TopLevelControl {
+		return false
+	}
+	permissive := &syntax.FileOptions{
+		Set: true, While: true, TopLevelControl: true, GlobalReassign: true, Recursion: true,
+	}
+	f, err := permissive.Parse("autofix check", program, 0)
+	if err != nil {
+		return false
+	}
+	for _, stmt := range f.Stmts {
+		switch stmt.(type) {
+		case *syntax.ForStmt, *syntax.IfStmt, *syntax.WhileStmt:
+			return true
+		}
+	}
+	return false
+}
+
+// wrapTopLevelInMain indents every line of program that isn't a top-level
+// load() call and wraps it in a main() function, since load() statements
+// must stay at module level. A line that starts inside an open (triple-quoted)
+// string literal is left untouched instead, so indenting doesn't change the
+// literal's actual value.
+func wrapTopLevelInMain(program string) string {
+	spans := stringSpans(program)
+	inSpan := func(offset int) bool {
+		for _, sp := range spans {
+			if offset >= sp[0] && offset < sp[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var loads, body []string
+	offset := 0
+	for _, line := range strings.Split(program, "\n") {
+		switch {
+		case inSpan(offset):
+			body = append(body, line)
+		case strings.HasPrefix(strings.TrimSpace(line), "load("):
+			loads = append(loads, line)
+		default:
+			body = append(body, "    "+line)
+		}
+		offset += len(line) + 1 // +1 for the newline Split consumed
+	}
+
+	var out strings.Builder
+	for _, l := range loads {
+		out.WriteString(l)
+		out.WriteRune('\n')
+	}
+	out.WriteString("def main():\n")
+	out.WriteString(strings.Join(body, "\n"))
+	out.WriteString("\nmain()\n")
+	return out.String()
+}
+
+// stringSpans returns the [start, end) byte ranges of every string literal
+// in program, skipping over "#" comments so an apostrophe or quote inside a
+// comment isn't mistaken for the start of one.
+func stringSpans(program string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(program); {
+		switch c := program[i]; {
+		case c == '#':
+			if end := strings.IndexByte(program[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(program)
+			}
+		case c == '"' || c == '\'':
+			end := stringLiteralEnd(program, i)
+			spans = append(spans, [2]int{i, end})
+			i = end
+		default:
+			i++
+		}
+	}
+	return spans
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alexmdac/starlark-mcp/mcptest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAutofixProgram(t *testing.T) {
+	tests := []struct {
+		name        string
+		program     string
+		wantContain string
+		wantChanges int
+	}{
+		{
+			name:        "exponent",
+			program:     `print(2 ** 3)`,
+			wantContain: "load(\"math\", \"pow\")\nprint(pow(2, 3))",
+			wantChanges: 1,
+		},
+		{
+			name:        "f-string",
+			program:     `name = "world"` + "\n" + `print(f"hello {name}")`,
+			wantContain: `print("hello %s" % (name,))`,
+			wantChanges: 1,
+		},
+		{
+			name:        "f-string multiple placeholders",
+			program:     `print(f"{a}+{b}")`,
+			wantContain: `print("%s+%s" % (a, b))`,
+			wantChanges: 1,
+		},
+		{
+			name:        "no change needed",
+			program:     `print("hello")`,
+			wantContain: `print("hello")`,
+			wantChanges: 0,
+		},
+		{
+			name:        "exponent-like text inside a string is left alone",
+			program:     `print("result: 2 ** 3 equals eight")`,
+			wantContain: `print("result: 2 ** 3 equals eight")`,
+			wantChanges: 0,
+		},
+		{
+			name:        "exponent-like text inside a comment is left alone",
+			program:     "# 2 ** 3 is eight\nprint(1)",
+			wantContain: "# 2 ** 3 is eight\nprint(1)",
+			wantChanges: 0,
+		},
+		{
+			name:        "real exponent alongside a misleading string is rewritten precisely",
+			program:     `print("base ** exp"); print(2 ** 3)`,
+			wantContain: "load(\"math\", \"pow\")\nprint(\"base ** exp\"); print(pow(2, 3))",
+			wantChanges: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewritten, changes := autofixProgram(tt.program)
+			if !strings.Contains(rewritten, tt.wantContain) {
+				t.Fatalf("rewritten program %q does not contain %q", rewritten, tt.wantContain)
+			}
+			if len(changes) != tt.wantChanges {
+				t.Fatalf("got %d changes (%v), want %d", len(changes), changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestAutofixProgram_WrapsTopLevelLoopUnderLegacyProfile(t *testing.T) {
+	t.Setenv(dialectProfileEnvVar, legacyProfile)
+
+	program := "for i in range(3):\n    print(i)"
+	rewritten, changes := autofixProgram(program)
+	if len(changes) != 1 {
+		t.Fatalf("got %d changes, want 1: %v", len(changes), changes)
+	}
+
+	result, err := executeStarlark(context.Background(), rewritten)
+	if err != nil {
+		t.Fatalf("executing autofixed program: %v\nprogram:\n%s", err, rewritten)
+	}
+	if result != "0\n1\n2\n" {
+		t.Fatalf("got %q, want %q", result, "0\n1\n2\n")
+	}
+}
+
+func TestAutofixProgram_WrapsTopLevelLoopWithoutCorruptingMultilineString(t *testing.T) {
+	t.Setenv(dialectProfileEnvVar, legacyProfile)
+
+	program := "s = \"\"\"line1\nline2\"\"\"\n" +
+		"for i in range(3):\n    print(i)\n" +
+		"print(s)"
+	rewritten, changes := autofixProgram(program)
+	if len(changes) != 1 {
+		t.Fatalf("got %d changes, want 1: %v", len(changes), changes)
+	}
+
+	result, err := executeStarlark(context.Background(), rewritten)
+	if err != nil {
+		t.Fatalf("executing autofixed program: %v\nprogram:\n%s", err, rewritten)
+	}
+	if result != "0\n1\n2\nline1\nline2\n" {
+		t.Fatalf("got %q, want %q\nrewritten program:\n%s", result, "0\n1\n2\nline1\nline2\n", rewritten)
+	}
+}
+
+func TestExecuteStarlark_Autofix(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print(2 ** 10)`,
+			TimeoutSecs: 30,
+			Autofix:     true,
+		},
+	}
+
+	text := mcptest.ExpectCallToolSuccess(t, client, params)
+	if text != "1024.0\n" {
+		t.Fatalf("got %q, want %q", text, "1024.0\n")
+	}
+}
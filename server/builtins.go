@@ -9,13 +9,18 @@ import (
 	"go.starlark.net/syntax"
 )
 
+// builtinModules lists the modules available to a loaded program via
+// load(), keyed by the name passed to load().
+var builtinModules = map[string]starlark.StringDict{
+	"math": math.Module.Members,
+}
+
 func loadBuiltinModule(thread *starlark.Thread, module string) (starlark.StringDict, error) {
-	switch module {
-	case "math":
-		return math.Module.Members, nil
-	default:
+	members, ok := builtinModules[module]
+	if !ok {
 		return nil, fmt.Errorf("no such module: %q", module)
 	}
+	return members, nil
 }
 
 //go:embed prelude.star
@@ -47,17 +52,21 @@ func compilePrelude() (starlark.StringDict, error) {
 	if err != nil {
 		return nil, err
 	}
-	// Only export symbols that don't start with "_".
+	// Only export symbols that don't start with "_". Freeze them: they're
+	// shared, read-only, across every execution, so this both documents
+	// that and lets Starlark skip mutability bookkeeping for them.
 	exported := make(starlark.StringDict, len(globals))
 	for name, val := range globals {
 		if len(name) > 0 && name[0] != '_' {
+			val.Freeze()
 			exported[name] = val
 		}
 	}
 	return exported, nil
 }
 
-// predeclared returns global symbols that do not need to be loaded.
+// predeclared returns global symbols that do not need to be loaded: the
+// prelude, plus any operator-configured extraConstants.
 func predeclared() starlark.StringDict {
-	return prelude
+	return predeclaredOnce()
 }
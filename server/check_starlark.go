@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const checkStarlarkName = "check-starlark"
+
+const checkStarlarkDescription = "Parses and resolves a Starlark program without executing it, returning " +
+	"structured diagnostics (line, column, message) for any syntax or resolution errors (e.g. undefined " +
+	"names). Use this to validate a long or generated program cheaply before spending a full " +
+	"execute-starlark call with a timeout."
+
+func addCheckStarlarkTool(server *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        checkStarlarkName,
+		Description: checkStarlarkDescription,
+	}
+	mcp.AddTool(server, tool, handleCheckStarlarkTool)
+}
+
+type checkStarlarkParams struct {
+	Program string `json:"program" jsonschema:"a Starlark program to check for syntax and resolution errors"`
+}
+
+// diagnostic is one syntax or resolution problem found in a checked
+// program, positioned the same way execute-starlark's own error messages
+// are (1-based line and column).
+type diagnostic struct {
+	Line    int32  `json:"line" jsonschema:"1-based line number"`
+	Col     int32  `json:"col" jsonschema:"1-based column number"`
+	Message string `json:"message" jsonschema:"the diagnostic message"`
+}
+
+// checkStarlarkResult is the structured output for check-starlark.
+// Diagnostics is empty for a program that parses and resolves cleanly;
+// note that a clean result doesn't guarantee the program runs without
+// error, only that it would get as far as execution.
+type checkStarlarkResult struct {
+	Diagnostics []diagnostic `json:"diagnostics,omitempty" jsonschema:"syntax or resolution problems found, empty if the program is clean"`
+}
+
+// handleCheckStarlarkTool recovers from panics, mirroring
+// handleExecuteStarlarkTool, though parsing and resolving untrusted source
+// is not expected to panic the way execution can.
+func handleCheckStarlarkTool(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args checkStarlarkParams,
+) (result *mcp.CallToolResult, out checkStarlarkResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, out, err = nil, checkStarlarkResult{}, fmt.Errorf("panic during check: %v", r)
+		}
+	}()
+
+	f, parseErr := fileOptions().Parse("LLM supplied program", args.Program, 0)
+	if parseErr != nil {
+		diags := diagnosticsFromError(parseErr)
+		return checkStarlarkResultFromDiagnostics(diags), checkStarlarkResult{Diagnostics: diags}, nil
+	}
+
+	if resolveErr := resolve.File(f, isPredeclaredName, starlark.Universe.Has); resolveErr != nil {
+		diags := diagnosticsFromError(resolveErr)
+		return checkStarlarkResultFromDiagnostics(diags), checkStarlarkResult{Diagnostics: diags}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: "ok: no syntax or resolution errors"},
+		},
+	}, checkStarlarkResult{}, nil
+}
+
+// isPredeclaredName reports whether name is one of the dialect's
+// predeclared builtins, as opposed to a Starlark universal (len, range,
+// etc.) which resolve.File checks separately via starlark.Universe.Has.
+func isPredeclaredName(name string) bool {
+	_, ok := predeclared()[name]
+	return ok
+}
+
+// diagnosticsFromError converts a syntax.Error or resolve.ErrorList (the
+// two error shapes fileOptions().Parse and resolve.File can return) into
+// diagnostics. Any other error shape becomes a single diagnostic with no
+// position, so a future upstream change can't silently drop the message.
+func diagnosticsFromError(err error) []diagnostic {
+	if list, ok := err.(resolve.ErrorList); ok {
+		diags := make([]diagnostic, len(list))
+		for i, e := range list {
+			diags[i] = diagnostic{Line: e.Pos.Line, Col: e.Pos.Col, Message: e.Msg}
+		}
+		return diags
+	}
+	if se, ok := err.(syntax.Error); ok {
+		return []diagnostic{{Line: se.Pos.Line, Col: se.Pos.Col, Message: se.Msg}}
+	}
+	return []diagnostic{{Message: err.Error()}}
+}
+
+// checkStarlarkResultFromDiagnostics renders diagnostics as the tool's text
+// content, one "line:col: message" per line, so a client reading only the
+// text still sees everything the structured output carries.
+func checkStarlarkResultFromDiagnostics(diags []diagnostic) *mcp.CallToolResult {
+	lines := make([]string, len(diags))
+	for i, d := range diags {
+		lines[i] = fmt.Sprintf("%d:%d: %s", d.Line, d.Col, d.Message)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: strings.Join(lines, "\n")},
+		},
+	}
+}
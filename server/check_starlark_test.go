@@ -0,0 +1,94 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexmdac/starlark-mcp/mcptest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCheckStarlark_Clean(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: checkStarlarkName,
+		Arguments: checkStarlarkParams{
+			Program: "x = 1 + 2\nprint(x)",
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("expected StructuredContent to be a map, got %#v", res.StructuredContent)
+	}
+	if _, ok := sc["diagnostics"]; ok {
+		t.Fatalf("expected no diagnostics for a clean program, got %#v", sc["diagnostics"])
+	}
+}
+
+func TestCheckStarlark_SyntaxError(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: checkStarlarkName,
+		Arguments: checkStarlarkParams{
+			Program: "def f(:\n  pass",
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed (with diagnostics), but it failed. Full result: %#v", res)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	diags, ok := sc["diagnostics"].([]any)
+	if !ok || len(diags) != 1 {
+		t.Fatalf("expected exactly one diagnostic, got %#v", sc["diagnostics"])
+	}
+	first := diags[0].(map[string]any)
+	if first["line"] != float64(1) {
+		t.Fatalf("expected line 1, got %#v", first["line"])
+	}
+	text := mcptest.ExpectTextContent(t, res)
+	if !strings.Contains(text, "1:") {
+		t.Fatalf("expected text content to report the line, got %q", text)
+	}
+}
+
+func TestCheckStarlark_UndefinedName(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: checkStarlarkName,
+		Arguments: checkStarlarkParams{
+			Program: "print(undefined_name)",
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed (with diagnostics), but it failed. Full result: %#v", res)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	diags, ok := sc["diagnostics"].([]any)
+	if !ok || len(diags) != 1 {
+		t.Fatalf("expected exactly one diagnostic, got %#v", sc["diagnostics"])
+	}
+	first := diags[0].(map[string]any)
+	if !strings.Contains(first["message"].(string), "undefined: undefined_name") {
+		t.Fatalf("expected message to name the undefined variable, got %#v", first["message"])
+	}
+}
+
+func TestCheckStarlark_DoesNotExecute(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: checkStarlarkName,
+		Arguments: checkStarlarkParams{
+			Program: `fail("this would blow up if executed")`,
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected check-starlark to accept a program that would fail at runtime, got error. Full result: %#v", res)
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sync"
+
+	starlarkjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+)
+
+// constantsEnvVar lets an operator predeclare extra constants (e.g.
+// ENV = "prod", feature flags, small reference datasets) for every
+// execution, for deployments that want domain-specific globals without
+// forking the server. The value is a JSON object mapping name to value.
+const constantsEnvVar = "STARLARK_MCP_CONSTANTS"
+
+// extraConstants holds the constants parsed from constantsEnvVar, or nil
+// if it's unset.
+var extraConstants starlark.StringDict
+
+func init() {
+	raw := os.Getenv(constantsEnvVar)
+	if raw == "" {
+		return
+	}
+	var err error
+	extraConstants, err = parseConstants(raw)
+	if err != nil {
+		panic(fmt.Sprintf("%s: %v", constantsEnvVar, err))
+	}
+}
+
+// parseConstants decodes raw as a JSON object and returns its entries as
+// frozen Starlark values, ready to predeclare.
+func parseConstants(raw string) (starlark.StringDict, error) {
+	decode, ok := starlarkjson.Module.Members["decode"].(*starlark.Builtin)
+	if !ok {
+		return nil, fmt.Errorf("internal error: json.decode is not a builtin")
+	}
+	v, err := starlark.Call(&starlark.Thread{}, decode, starlark.Tuple{starlark.String(raw)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := v.(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("must be a JSON object, got %s", v.Type())
+	}
+
+	constants := make(starlark.StringDict, dict.Len())
+	for _, item := range dict.Items() {
+		key, ok := item[0].(starlark.String)
+		if !ok {
+			return nil, fmt.Errorf("keys must be strings, got %s", item[0].Type())
+		}
+		item[1].Freeze()
+		constants[string(key)] = item[1]
+	}
+	return constants, nil
+}
+
+// predeclaredOnce computes the prelude merged with any operator-configured
+// extraConstants the first time it's needed, and caches the result so
+// predeclared() stays a cheap lookup on every later execution. It can't be
+// a plain package-level var, since that would run before builtins.go's
+// init() has populated prelude.
+var predeclaredOnce = sync.OnceValue(mergePredeclared)
+
+func mergePredeclared() starlark.StringDict {
+	if len(extraConstants) == 0 {
+		return prelude
+	}
+	merged := make(starlark.StringDict, len(prelude)+len(extraConstants))
+	maps.Copy(merged, prelude)
+	maps.Copy(merged, extraConstants)
+	return merged
+}
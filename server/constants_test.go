@@ -0,0 +1,36 @@
+package server
+
+import (
+	"testing"
+
+	"go.starlark.net/starlark"
+)
+
+func TestParseConstants(t *testing.T) {
+	constants, err := parseConstants(`{"ENV": "prod", "MAX_RETRIES": 3, "FEATURE_X": true}`)
+	if err != nil {
+		t.Fatalf("parseConstants: %v", err)
+	}
+
+	if got, want := constants["ENV"], starlark.String("prod"); got != want {
+		t.Fatalf("ENV = %v, want %v", got, want)
+	}
+	if got, want := constants["MAX_RETRIES"], starlark.MakeInt(3); got.(starlark.Int).String() != want.String() {
+		t.Fatalf("MAX_RETRIES = %v, want %v", got, want)
+	}
+	if got, want := constants["FEATURE_X"], starlark.Bool(true); got != want {
+		t.Fatalf("FEATURE_X = %v, want %v", got, want)
+	}
+}
+
+func TestParseConstants_NotAnObject(t *testing.T) {
+	if _, err := parseConstants(`[1, 2, 3]`); err == nil {
+		t.Fatal("expected an error for a non-object top-level value")
+	}
+}
+
+func TestParseConstants_Invalid(t *testing.T) {
+	if _, err := parseConstants(`not json`); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+//go:embed cookbook/*.star
+var cookbookFS embed.FS
+
+const cookbookURIPrefix = "starlark://cookbook"
+
+// cookbookCategories describes each canonical solution resource, in the
+// order the index resource lists them.
+var cookbookCategories = []struct {
+	name        string
+	description string
+}{
+	{"dp", "Dynamic programming (bottom-up tables)"},
+	{"graphs", "Graph traversal (BFS over adjacency lists)"},
+	{"strings", "String processing without f-strings or character iteration"},
+}
+
+// addCookbookResources registers starlark://cookbook and one
+// starlark://cookbook/{category} resource per entry in cookbookCategories,
+// giving clients canonical solutions written in this server's dialect that
+// they can inject to boost model success on hard tiers.
+func addCookbookResources(s *mcp.Server) {
+	s.AddResource(&mcp.Resource{
+		URI:         cookbookURIPrefix,
+		Name:        "cookbook-index",
+		Description: "Index of available starlark://cookbook/{category} resources",
+		MIMEType:    "text/plain",
+	}, handleCookbookIndex)
+
+	for _, c := range cookbookCategories {
+		s.AddResource(&mcp.Resource{
+			URI:         cookbookURIPrefix + "/" + c.name,
+			Name:        "cookbook-" + c.name,
+			Description: c.description,
+			MIMEType:    "text/x-starlark",
+		}, handleCookbookCategory(c.name))
+	}
+}
+
+func handleCookbookIndex(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	var lines []string
+	for _, c := range cookbookCategories {
+		lines = append(lines, fmt.Sprintf("%s/%s - %s", cookbookURIPrefix, c.name, c.description))
+	}
+	sort.Strings(lines)
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      cookbookURIPrefix,
+				MIMEType: "text/plain",
+				Text:     strings.Join(lines, "\n") + "\n",
+			},
+		},
+	}, nil
+}
+
+func handleCookbookCategory(name string) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		src, err := cookbookFS.ReadFile("cookbook/" + name + ".star")
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{
+					URI:      req.Params.URI,
+					MIMEType: "text/x-starlark",
+					Text:     string(src),
+				},
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCookbookResources_Execute(t *testing.T) {
+	for _, c := range cookbookCategories {
+		t.Run(c.name, func(t *testing.T) {
+			src, err := cookbookFS.ReadFile("cookbook/" + c.name + ".star")
+			if err != nil {
+				t.Fatalf("reading cookbook/%s.star: %v", c.name, err)
+			}
+			if _, err := executeStarlark(t.Context(), string(src)); err != nil {
+				t.Fatalf("executing cookbook/%s.star: %v", c.name, err)
+			}
+		})
+	}
+}
+
+func TestCookbookResources_Registered(t *testing.T) {
+	client := startTestServer(t)
+
+	res, err := client.ListResources(t.Context(), &mcp.ListResourcesParams{})
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	var cookbookResources int
+	for _, r := range res.Resources {
+		if strings.HasPrefix(r.URI, cookbookURIPrefix) {
+			cookbookResources++
+		}
+	}
+	// 1 index + 1 per category.
+	want := 1 + len(cookbookCategories)
+	if cookbookResources != want {
+		t.Fatalf("got %d cookbook resources, want %d", cookbookResources, want)
+	}
+
+	got, err := client.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: cookbookURIPrefix + "/dp"})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(got.Contents) != 1 || !strings.Contains(got.Contents[0].Text, "knapsack") {
+		t.Fatalf("unexpected dp cookbook contents: %#v", got.Contents)
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.starlark.net/syntax"
+)
+
+// dialectProfileEnvVar selects an alternate dialect profile for
+// execute-starlark, primarily so the eval harness can measure how model
+// pass rates vary with server capabilities. Most deployments should leave
+// it unset.
+const dialectProfileEnvVar = "STARLARK_MCP_DIALECT_PROFILE"
+
+// legacyProfile disables the while loops, top-level control flow, and
+// recursion extensions, matching the restrictions of older Starlark
+// dialects.
+const legacyProfile = "legacy"
+
+// fileOptions returns the syntax.FileOptions for LLM-supplied programs,
+// selected via dialectProfileEnvVar. An unset or unrecognized value
+// selects the default (current) profile.
+func fileOptions() *syntax.FileOptions {
+	if os.Getenv(dialectProfileEnvVar) == legacyProfile {
+		return &syntax.FileOptions{
+			Set:            true,
+			GlobalReassign: true,
+		}
+	}
+	return &syntax.FileOptions{
+		Set:             true,
+		While:           true,
+		TopLevelControl: true,
+		GlobalReassign:  true,
+		Recursion:       true,
+	}
+}
+
+const describeDialectName = "describe-dialect"
+
+const describeDialectDescription = "Reports the enabled syntax options, execution limits, and available " +
+	"modules for execute-starlark, so a client can adapt the programs it generates to this deployment " +
+	"without trial and error."
+
+func addDescribeDialectTool(server *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        describeDialectName,
+		Description: describeDialectDescription,
+	}
+	mcp.AddTool(server, tool, handleDescribeDialectTool)
+}
+
+type describeDialectParams struct{}
+
+type dialectLimits struct {
+	MaxOutputBytes    int    `json:"max_output_bytes" jsonschema:"maximum size of a program's combined print() output"`
+	MaxExecutionSteps uint64 `json:"max_execution_steps" jsonschema:"maximum Starlark computation steps before execution is cancelled"`
+}
+
+type describeDialectResult struct {
+	Profile          string          `json:"profile" jsonschema:"the active dialect profile: \"default\" or \"legacy\""`
+	SyntaxOptions    map[string]bool `json:"syntax_options" jsonschema:"enabled syntax.FileOptions flags"`
+	Limits           dialectLimits   `json:"limits"`
+	Modules          []string        `json:"modules" jsonschema:"modules that can be imported via load()"`
+	PredeclaredNames []string        `json:"predeclared_names" jsonschema:"names available without load(), beyond the Starlark universe"`
+}
+
+func handleDescribeDialectTool(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args describeDialectParams,
+) (*mcp.CallToolResult, describeDialectResult, error) {
+	opts := fileOptions()
+
+	profile := "default"
+	if os.Getenv(dialectProfileEnvVar) == legacyProfile {
+		profile = legacyProfile
+	}
+
+	var modules []string
+	for name := range builtinModules {
+		modules = append(modules, name)
+	}
+	sort.Strings(modules)
+
+	var names []string
+	for name := range predeclared() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return nil, describeDialectResult{
+		Profile: profile,
+		SyntaxOptions: map[string]bool{
+			"set":                 opts.Set,
+			"while":               opts.While,
+			"top_level_control":   opts.TopLevelControl,
+			"global_reassign":     opts.GlobalReassign,
+			"load_binds_globally": opts.LoadBindsGlobally,
+			"recursion":           opts.Recursion,
+		},
+		Limits: dialectLimits{
+			MaxOutputBytes:    maxOutputLen,
+			MaxExecutionSteps: maxExecutionSteps,
+		},
+		Modules:          modules,
+		PredeclaredNames: names,
+	}, nil
+}
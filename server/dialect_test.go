@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alexmdac/starlark-mcp/mcptest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDialectProfile_Legacy(t *testing.T) {
+	t.Setenv(dialectProfileEnvVar, legacyProfile)
+
+	_, err := executeStarlark(context.Background(), `while True: pass`)
+	if err == nil {
+		t.Fatal("expected while loop to be rejected under the legacy profile")
+	}
+	if !strings.Contains(err.Error(), "does not support while loops") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDialectProfile_Full(t *testing.T) {
+	result, err := executeStarlark(context.Background(), `
+def main():
+    x = 0
+    while x < 3:
+        x += 1
+    print(x)
+main()`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(result) != "3" {
+		t.Fatalf("expected %q, got %q", "3", result)
+	}
+}
+
+func TestDescribeDialect(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name:      describeDialectName,
+		Arguments: describeDialectParams{},
+	}
+
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("expected StructuredContent to be a map, got %#v", res.StructuredContent)
+	}
+
+	if got := sc["profile"]; got != "default" {
+		t.Fatalf("profile = %v, want %q", got, "default")
+	}
+
+	syntaxOptions, ok := sc["syntax_options"].(map[string]any)
+	if !ok || syntaxOptions["while"] != true {
+		t.Fatalf("unexpected syntax_options: %#v", sc["syntax_options"])
+	}
+
+	modules, ok := sc["modules"].([]any)
+	if !ok || len(modules) != 1 || modules[0] != "math" {
+		t.Fatalf("unexpected modules: %#v", sc["modules"])
+	}
+
+	limits, ok := sc["limits"].(map[string]any)
+	if !ok || limits["max_execution_steps"] != float64(maxExecutionSteps) {
+		t.Fatalf("unexpected limits: %#v", sc["limits"])
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+//go:embed docs/*.md
+var docsFS embed.FS
+
+const docsURIPrefix = "starlark://docs"
+
+// docTopics describes each per-category documentation resource, split out
+// of description.md so token-sensitive clients can fetch only the section
+// relevant to the error or feature they're asking about.
+var docTopics = []struct {
+	name        string
+	description string
+}{
+	{"control-flow", "Restrictions on classes, I/O, imports, and operator chaining"},
+	{"strings", "Supported and unsupported string methods"},
+	{"math", "The math module and the lack of a ** operator"},
+	{"formatting", "String formatting without f-strings"},
+}
+
+// addDocResources registers starlark://docs and one
+// starlark://docs/{topic} resource per entry in docTopics.
+func addDocResources(s *mcp.Server) {
+	s.AddResource(&mcp.Resource{
+		URI:         docsURIPrefix,
+		Name:        "docs-index",
+		Description: "Index of available starlark://docs/{topic} resources",
+		MIMEType:    "text/plain",
+	}, handleDocsIndex)
+
+	for _, t := range docTopics {
+		s.AddResource(&mcp.Resource{
+			URI:         docsURIPrefix + "/" + t.name,
+			Name:        "docs-" + t.name,
+			Description: t.description,
+			MIMEType:    "text/markdown",
+		}, handleDocTopic(t.name))
+	}
+}
+
+func handleDocsIndex(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	var lines []string
+	for _, t := range docTopics {
+		lines = append(lines, fmt.Sprintf("%s/%s - %s", docsURIPrefix, t.name, t.description))
+	}
+	sort.Strings(lines)
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      docsURIPrefix,
+				MIMEType: "text/plain",
+				Text:     strings.Join(lines, "\n") + "\n",
+			},
+		},
+	}, nil
+}
+
+func handleDocTopic(name string) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		src, err := docsFS.ReadFile("docs/" + name + ".md")
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{
+					URI:      req.Params.URI,
+					MIMEType: "text/markdown",
+					Text:     string(src),
+				},
+			},
+		}, nil
+	}
+}
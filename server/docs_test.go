@@ -0,0 +1,28 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDocResources_Registered(t *testing.T) {
+	client := startTestServer(t)
+
+	got, err := client.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: docsURIPrefix + "/math"})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(got.Contents) != 1 || !strings.Contains(got.Contents[0].Text, "Math module") {
+		t.Fatalf("unexpected math doc contents: %#v", got.Contents)
+	}
+
+	index, err := client.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: docsURIPrefix})
+	if err != nil {
+		t.Fatalf("ReadResource index: %v", err)
+	}
+	if len(index.Contents) != 1 || !strings.Contains(index.Contents[0].Text, "formatting") {
+		t.Fatalf("unexpected docs index contents: %#v", index.Contents)
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// NewThread returns a *starlark.Thread wired to the same module loader as
+// execute-starlark, for embedders (e.g. the REPL) that want to drive
+// execution themselves instead of going through Execute.
+func NewThread(print func(thread *starlark.Thread, msg string)) *starlark.Thread {
+	return &starlark.Thread{
+		Print: print,
+		Load:  loadBuiltinModule,
+	}
+}
+
+// Predeclared returns the predeclared global symbols available to
+// Starlark programs run by this server.
+func Predeclared() starlark.StringDict {
+	return predeclared()
+}
+
+// FileOptions returns the syntax.FileOptions dialect used to execute
+// Starlark programs.
+func FileOptions() *syntax.FileOptions {
+	return fileOptions()
+}
+
+// LoadModule returns the members of a builtin module by name, the same set
+// a Starlark program would get from load("<name>", ...).
+func LoadModule(name string) (starlark.StringDict, error) {
+	return loadBuiltinModule(nil, name)
+}
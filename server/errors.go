@@ -0,0 +1,139 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// unsupportedMethods maps attribute/method names that LLMs commonly assume
+// exist (because they exist in Python) but that this dialect deliberately
+// doesn't implement, to a short pointer at the supported alternative. Kept
+// in sync with the "Missing" list in description.md.
+var unsupportedMethods = map[string]string{
+	"rjust":  `not supported; pad manually, e.g. ("%5s" % s)`,
+	"ljust":  `not supported; pad manually, e.g. ("%-5s" % s)`,
+	"center": "not supported; pad manually on both sides",
+}
+
+var (
+	undefinedRe    = regexp.MustCompile(`undefined: (\w+)$`)
+	noSuchMethodRe = regexp.MustCompile(`has no \.(\w+) field or method$`)
+	fstringRe      = regexp.MustCompile(`\bf["']`)
+)
+
+// enrichExecutionError rewrites common Starlark errors to append a
+// "did you mean" suggestion or a pointer to a supported alternative, so a
+// client can often fix its program from the error text alone rather than
+// guessing and retrying blind. program is the source that produced err,
+// used to disambiguate errors whose message alone isn't specific enough
+// (e.g. an f-string misparsing as a name followed by a string).
+func enrichExecutionError(err error, program string) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+
+	if m := undefinedRe.FindStringSubmatch(msg); m != nil && !strings.Contains(msg, "did you mean") {
+		if guess := nearestName(m[1], candidateNames()); guess != "" {
+			return fmt.Errorf("%s (did you mean %s?)", msg, guess)
+		}
+	}
+
+	if m := noSuchMethodRe.FindStringSubmatch(msg); m != nil {
+		if hint, ok := unsupportedMethods[m[1]]; ok {
+			return fmt.Errorf("%s (%s)", msg, hint)
+		}
+	}
+
+	if strings.Contains(msg, "string value is not iterable") {
+		return fmt.Errorf("%s (strings aren't iterable; use s.elems())", msg)
+	}
+
+	if strings.Contains(msg, "want ','") && strings.Contains(msg, "'**'") {
+		return fmt.Errorf(`%s (the "**" operator isn't supported; use x * x or load("math", "pow"))`, msg)
+	}
+
+	if strings.Contains(msg, "want ','") && strings.Contains(msg, "string literal") && fstringRe.MatchString(program) {
+		return fmt.Errorf(`%s (f-strings aren't supported; use "%%s" %% x or concatenation)`, msg)
+	}
+
+	if strings.HasSuffix(msg, "not within a function") {
+		return fmt.Errorf("%s (wrap this logic in a def and call it, or see starlark://docs/control-flow)", msg)
+	}
+
+	if strings.Contains(msg, "does not support while loops") {
+		return fmt.Errorf("%s (this dialect profile has while loops disabled)", msg)
+	}
+
+	if strings.Contains(msg, "does not support sets") {
+		return fmt.Errorf("%s (this dialect profile has the set() builtin disabled)", msg)
+	}
+
+	if strings.Contains(msg, "called recursively") {
+		return fmt.Errorf("%s (recursion isn't enabled under this dialect profile; rewrite iteratively)", msg)
+	}
+
+	return err
+}
+
+// candidateNames lists the predeclared and universal names a spelling
+// suggestion can be drawn from. It's recomputed per error rather than
+// cached, since it's only evaluated on the (rare) error path.
+func candidateNames() []string {
+	names := make([]string, 0, len(starlark.Universe)+len(predeclared()))
+	for name := range starlark.Universe {
+		names = append(names, name)
+	}
+	for name := range predeclared() {
+		names = append(names, name)
+	}
+	return names
+}
+
+// nearestName returns the candidate closest to name by Levenshtein
+// distance, or "" if none are close enough to be a plausible typo.
+func nearestName(name string, candidates []string) string {
+	best := ""
+	bestDist := (len(name) + 1) / 2 // allow up to 50% typos, as go.starlark.net's own spellchecker does
+	for _, c := range candidates {
+		if d := levenshtein(name, c, bestDist); d < bestDist {
+			bestDist = d
+			best = c
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b, capped at max:
+// once the true distance is known to exceed max, it returns max without
+// finishing the computation.
+func levenshtein(a, b string, max int) int {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	prev := make([]int, len(a)+1)
+	for i := range prev {
+		prev[i] = i
+	}
+	for i := 1; i <= len(b); i++ {
+		curr := make([]int, len(a)+1)
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(a); j++ {
+			cost := 1
+			if a[j-1] == b[i-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, min(curr[j-1]+1, prev[j-1]+cost))
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max
+		}
+		prev = curr
+	}
+	return prev[len(a)]
+}
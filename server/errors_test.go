@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEnrichExecutionError(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		want    string
+	}{
+		{
+			name:    "undefined with close predeclared match",
+			program: `print(len_(""))`,
+			want:    "did you mean len?",
+		},
+		{
+			name:    "unsupported string method",
+			program: `print("x".rjust(5))`,
+			want:    "not supported; pad manually",
+		},
+		{
+			name:    "string not iterable",
+			program: "for c in \"abc\":\n    print(c)",
+			want:    "use s.elems()",
+		},
+		{
+			name:    "exponent operator",
+			program: `print(2 ** 3)`,
+			want:    `"**" operator isn't supported`,
+		},
+		{
+			name:    "f-string",
+			program: `name = "world"` + "\n" + `print(f"hello {name}")`,
+			want:    "f-strings aren't supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeStarlark(context.Background(), tt.program)
+			if err == nil {
+				t.Fatalf("expected an error, got none")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %q does not contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEnrichExecutionError_LegacyProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		want    string
+	}{
+		{
+			name:    "top-level for",
+			program: "for i in range(3):\n    print(i)",
+			want:    "wrap this logic in a def",
+		},
+		{
+			name:    "while loop",
+			program: `while True: pass`,
+			want:    "while loops disabled",
+		},
+		{
+			name: "recursion",
+			program: "def f(n):\n    if n == 0:\n        return 1\n    return n * f(n - 1)\n" +
+				"print(f(5))",
+			want: "recursion isn't enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(dialectProfileEnvVar, legacyProfile)
+			_, err := executeStarlark(context.Background(), tt.program)
+			if err == nil {
+				t.Fatalf("expected an error, got none")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %q does not contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEnrichExecutionError_NoSpuriousSuggestion(t *testing.T) {
+	_, err := executeStarlark(context.Background(), `print(sum([1, 2, 3]))`)
+	if err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+	if strings.Contains(err.Error(), "did you mean") {
+		t.Fatalf("unexpected suggestion in error: %q", err.Error())
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	jsonlib "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+)
+
+const evalStarlarkExpressionName = "eval-starlark-expression"
+
+const evalStarlarkExpressionDescription = "Evaluates a single Starlark expression (e.g. \"1 + 2\" or " +
+	"\"[x * x for x in range(5)]\") and returns its value, without needing a whole program and a " +
+	"print() call for a one-off computation."
+
+func addEvalStarlarkExpressionTool(server *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        evalStarlarkExpressionName,
+		Description: evalStarlarkExpressionDescription,
+	}
+	mcp.AddTool(server, tool, handleEvalStarlarkExpressionTool)
+}
+
+type evalStarlarkExpressionParams struct {
+	Expression  string  `json:"expression" jsonschema:"a single valid Starlark expression"`
+	TimeoutSecs float32 `json:"timeout_secs" jsonschema:"execution timeout in seconds"`
+}
+
+func (p evalStarlarkExpressionParams) validate() error {
+	if p.TimeoutSecs <= 0.0 {
+		return fmt.Errorf("invalid timeout: %f", p.TimeoutSecs)
+	}
+	return nil
+}
+
+func (p evalStarlarkExpressionParams) timeout() time.Duration {
+	return time.Duration(p.TimeoutSecs * float32(time.Second))
+}
+
+// evalStarlarkExpressionResult is the structured output for
+// eval-starlark-expression. The value's repr always goes out as the tool's
+// text content; JSON is the same value re-encoded as JSON, when it's a kind
+// json.encode can handle (omitted for e.g. functions).
+type evalStarlarkExpressionResult struct {
+	JSON string `json:"json,omitempty" jsonschema:"the value as JSON, omitted if the value isn't JSON-representable (e.g. a function)"`
+}
+
+// handleEvalStarlarkExpressionTool recovers from panics in the evaluation
+// path, mirroring handleExecuteStarlarkTool, so one bad expression can't
+// take down the whole session.
+func handleEvalStarlarkExpressionTool(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args evalStarlarkExpressionParams,
+) (result *mcp.CallToolResult, out evalStarlarkExpressionResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, out, err = nil, evalStarlarkExpressionResult{}, fmt.Errorf("panic during evaluation: %v", r)
+		}
+	}()
+
+	if err := args.validate(); err != nil {
+		return nil, evalStarlarkExpressionResult{}, err
+	}
+
+	ctx, done := context.WithTimeout(ctx, args.timeout())
+	defer done()
+
+	thread := &starlark.Thread{Load: loadBuiltinModule}
+	thread.SetMaxExecutionSteps(maxExecutionSteps)
+	context.AfterFunc(ctx, func() {
+		reason := ""
+		if err := ctx.Err(); err != nil {
+			reason = err.Error()
+		}
+		thread.Cancel(reason)
+	})
+
+	value, err := starlark.EvalOptions(fileOptions(), thread, "LLM supplied expression", args.Expression, predeclared())
+	if err != nil {
+		return nil, evalStarlarkExpressionResult{}, fmt.Errorf("failed to evaluate expression: %v", enrichExecutionError(err, args.Expression))
+	}
+
+	jsonValue, _ := jsonEncode(thread, value)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: value.String()},
+		},
+	}, evalStarlarkExpressionResult{JSON: jsonValue}, nil
+}
+
+// jsonEncode encodes value as a JSON string using go.starlark.net's own
+// json.encode implementation, so this tool's notion of "convertible to
+// JSON" (a Starlark value json.encode can handle: None/bool/int/float/
+// string/list/tuple/dict) doesn't drift from the language's own definition
+// of it. err is non-nil for values json.encode can't handle, e.g. a
+// function.
+func jsonEncode(thread *starlark.Thread, value starlark.Value) (string, error) {
+	encoded, err := starlark.Call(thread, jsonlib.Module.Members["encode"], starlark.Tuple{value}, nil)
+	if err != nil {
+		return "", err
+	}
+	s, ok := encoded.(starlark.String)
+	if !ok {
+		return "", fmt.Errorf("json.encode returned non-string %T", encoded)
+	}
+	return s.GoString(), nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexmdac/starlark-mcp/mcptest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestEvalStarlarkExpression(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: evalStarlarkExpressionName,
+		Arguments: evalStarlarkExpressionParams{
+			Expression:  `1 + 2`,
+			TimeoutSecs: 5,
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	text := mcptest.ExpectTextContent(t, res)
+	if text != "3" {
+		t.Fatalf("expected repr %q, got %q", "3", text)
+	}
+	sc, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("expected StructuredContent to be a map, got %#v", res.StructuredContent)
+	}
+	if sc["json"] != "3" {
+		t.Fatalf("expected json %q, got %#v", "3", sc["json"])
+	}
+}
+
+func TestEvalStarlarkExpression_List(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: evalStarlarkExpressionName,
+		Arguments: evalStarlarkExpressionParams{
+			Expression:  `[x * x for x in range(4)]`,
+			TimeoutSecs: 5,
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	text := mcptest.ExpectTextContent(t, res)
+	if text != "[0, 1, 4, 9]" {
+		t.Fatalf("expected repr %q, got %q", "[0, 1, 4, 9]", text)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	if sc["json"] != "[0,1,4,9]" {
+		t.Fatalf("expected json %q, got %#v", "[0,1,4,9]", sc["json"])
+	}
+}
+
+func TestEvalStarlarkExpression_NotJSONEncodable(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: evalStarlarkExpressionName,
+		Arguments: evalStarlarkExpressionParams{
+			Expression:  `len`,
+			TimeoutSecs: 5,
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	text := mcptest.ExpectTextContent(t, res)
+	if !strings.Contains(text, "built-in function") {
+		t.Fatalf("expected repr to describe a builtin function, got %q", text)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	if _, ok := sc["json"]; ok {
+		t.Fatalf("expected no json field for a non-encodable value, got %#v", sc["json"])
+	}
+}
+
+func TestEvalStarlarkExpression_InvalidExpression(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: evalStarlarkExpressionName,
+		Arguments: evalStarlarkExpressionParams{
+			Expression:  `def f(): pass`,
+			TimeoutSecs: 5,
+		},
+	}
+	errorText := mcptest.ExpectCallToolError(t, client, params)
+	if !strings.Contains(errorText, "failed to evaluate expression") {
+		t.Fatalf("expected error to contain %q, but got %q", "failed to evaluate expression", errorText)
+	}
+}
+
+func TestEvalStarlarkExpression_InvalidTimeout(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: evalStarlarkExpressionName,
+		Arguments: evalStarlarkExpressionParams{
+			Expression:  `1`,
+			TimeoutSecs: -1.0,
+		},
+	}
+	errorText := mcptest.ExpectCallToolError(t, client, params)
+	if !strings.Contains(errorText, "invalid timeout") {
+		t.Fatalf("expected error to contain %q, but got %q", "invalid timeout", errorText)
+	}
+}
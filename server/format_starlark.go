@@ -0,0 +1,294 @@
+package server
+
+import (
+	"strings"
+
+	"go.starlark.net/syntax"
+)
+
+// formatStarlarkSource re-renders a parsed program from its AST with
+// consistent 4-space indentation and operator spacing, the way buildifier
+// normalizes BUILD/.bzl files. It does not reflow long lines to a column
+// width, and it drops comments (the AST's commentsRef fields aren't
+// consulted): both are scope a future change can add, not semantic gaps in
+// what's printed.
+func formatStarlarkSource(f *syntax.File) string {
+	p := &formatPrinter{}
+	p.stmts(0, f.Stmts)
+	return p.buf.String()
+}
+
+type formatPrinter struct {
+	buf strings.Builder
+}
+
+func (p *formatPrinter) indent(depth int) {
+	p.buf.WriteString(strings.Repeat("    ", depth))
+}
+
+// stmts prints a statement block, inserting a blank line around top-level
+// function definitions so a formatted file doesn't run them together.
+func (p *formatPrinter) stmts(depth int, stmts []syntax.Stmt) {
+	for i, s := range stmts {
+		if i > 0 && depth == 0 && (isDef(stmts[i-1]) || isDef(s)) {
+			p.buf.WriteByte('\n')
+		}
+		p.stmt(depth, s)
+	}
+}
+
+func isDef(s syntax.Stmt) bool {
+	_, ok := s.(*syntax.DefStmt)
+	return ok
+}
+
+func (p *formatPrinter) stmt(depth int, stmt syntax.Stmt) {
+	p.indent(depth)
+	switch s := stmt.(type) {
+	case *syntax.AssignStmt:
+		p.buf.WriteString(p.expr(s.LHS))
+		p.buf.WriteByte(' ')
+		p.buf.WriteString(s.Op.String())
+		p.buf.WriteByte(' ')
+		p.buf.WriteString(p.expr(s.RHS))
+		p.buf.WriteByte('\n')
+
+	case *syntax.ExprStmt:
+		p.buf.WriteString(p.expr(s.X))
+		p.buf.WriteByte('\n')
+
+	case *syntax.DefStmt:
+		p.buf.WriteString("def ")
+		p.buf.WriteString(s.Name.Name)
+		p.buf.WriteByte('(')
+		p.buf.WriteString(p.argList(s.Params))
+		p.buf.WriteString("):\n")
+		p.stmts(depth+1, s.Body)
+
+	case *syntax.IfStmt:
+		p.ifStmt(depth, s, "if")
+
+	case *syntax.ForStmt:
+		p.buf.WriteString("for ")
+		p.buf.WriteString(p.expr(s.Vars))
+		p.buf.WriteString(" in ")
+		p.buf.WriteString(p.expr(s.X))
+		p.buf.WriteString(":\n")
+		p.stmts(depth+1, s.Body)
+
+	case *syntax.WhileStmt:
+		p.buf.WriteString("while ")
+		p.buf.WriteString(p.expr(s.Cond))
+		p.buf.WriteString(":\n")
+		p.stmts(depth+1, s.Body)
+
+	case *syntax.LoadStmt:
+		// For each binding, From holds the quoted name as it appears in the
+		// loaded module and To holds the local identifier; they're only
+		// distinct (From.Name != To.Name) for an aliased "local=\"module\""
+		// binding, confirmed against the parser directly since this is the
+		// reverse of what LoadStmt's own field-name doc comments suggest.
+		p.buf.WriteString("load(")
+		p.buf.WriteString(s.Module.Raw)
+		for i, from := range s.From {
+			p.buf.WriteString(", ")
+			to := s.To[i]
+			if from.Name == to.Name {
+				p.buf.WriteString(quoteString(from.Name))
+			} else {
+				p.buf.WriteString(to.Name)
+				p.buf.WriteByte('=')
+				p.buf.WriteString(quoteString(from.Name))
+			}
+		}
+		p.buf.WriteString(")\n")
+
+	case *syntax.BranchStmt:
+		p.buf.WriteString(s.Token.String())
+		p.buf.WriteByte('\n')
+
+	case *syntax.ReturnStmt:
+		p.buf.WriteString("return")
+		if s.Result != nil {
+			p.buf.WriteByte(' ')
+			p.buf.WriteString(p.expr(s.Result))
+		}
+		p.buf.WriteByte('\n')
+	}
+}
+
+// ifStmt prints an if/elif/else chain. The parser desugars "elif" into a
+// single-statement False branch holding another *IfStmt; detecting that
+// shape is what lets an elif come back out as "elif" instead of a nested
+// "else:\n    if ...".
+func (p *formatPrinter) ifStmt(depth int, s *syntax.IfStmt, keyword string) {
+	p.buf.WriteString(keyword)
+	p.buf.WriteByte(' ')
+	p.buf.WriteString(p.expr(s.Cond))
+	p.buf.WriteString(":\n")
+	p.stmts(depth+1, s.True)
+
+	if len(s.False) == 1 {
+		if elif, ok := s.False[0].(*syntax.IfStmt); ok {
+			p.indent(depth)
+			p.ifStmt(depth, elif, "elif")
+			return
+		}
+	}
+	if len(s.False) > 0 {
+		p.indent(depth)
+		p.buf.WriteString("else:\n")
+		p.stmts(depth+1, s.False)
+	}
+}
+
+// expr renders a single expression on one line.
+func (p *formatPrinter) expr(e syntax.Expr) string {
+	switch e := e.(type) {
+	case *syntax.Ident:
+		return e.Name
+
+	case *syntax.Literal:
+		return e.Raw
+
+	case *syntax.ParenExpr:
+		return "(" + p.expr(e.X) + ")"
+
+	case *syntax.CallExpr:
+		return p.expr(e.Fn) + "(" + p.argList(e.Args) + ")"
+
+	case *syntax.DotExpr:
+		return p.expr(e.X) + "." + e.Name.Name
+
+	case *syntax.IndexExpr:
+		return p.expr(e.X) + "[" + p.expr(e.Y) + "]"
+
+	case *syntax.SliceExpr:
+		var b strings.Builder
+		b.WriteString(p.expr(e.X))
+		b.WriteByte('[')
+		if e.Lo != nil {
+			b.WriteString(p.expr(e.Lo))
+		}
+		b.WriteByte(':')
+		if e.Hi != nil {
+			b.WriteString(p.expr(e.Hi))
+		}
+		if e.Step != nil {
+			b.WriteByte(':')
+			b.WriteString(p.expr(e.Step))
+		}
+		b.WriteByte(']')
+		return b.String()
+
+	case *syntax.UnaryExpr:
+		if e.X == nil {
+			return e.Op.String() // bare "*" keyword-only marker in a param list
+		}
+		if e.Op == syntax.NOT {
+			return "not " + p.expr(e.X)
+		}
+		return e.Op.String() + p.expr(e.X)
+
+	case *syntax.BinaryExpr:
+		return p.expr(e.X) + " " + e.Op.String() + " " + p.expr(e.Y)
+
+	case *syntax.TupleExpr:
+		inner := p.exprList(e.List)
+		if len(e.List) == 1 {
+			inner += "," // a single-element tuple needs a trailing comma
+		}
+		if e.Lparen.IsValid() || len(e.List) == 0 {
+			return "(" + inner + ")"
+		}
+		return inner
+
+	case *syntax.ListExpr:
+		return "[" + p.exprList(e.List) + "]"
+
+	case *syntax.DictExpr:
+		return "{" + p.exprList(e.List) + "}"
+
+	case *syntax.DictEntry:
+		return p.expr(e.Key) + ": " + p.expr(e.Value)
+
+	case *syntax.LambdaExpr:
+		params := p.argList(e.Params)
+		if params == "" {
+			return "lambda: " + p.expr(e.Body)
+		}
+		return "lambda " + params + ": " + p.expr(e.Body)
+
+	case *syntax.CondExpr:
+		return p.expr(e.True) + " if " + p.expr(e.Cond) + " else " + p.expr(e.False)
+
+	case *syntax.Comprehension:
+		open, closeBracket := "[", "]"
+		if e.Curly {
+			open, closeBracket = "{", "}"
+		}
+		var b strings.Builder
+		b.WriteString(open)
+		b.WriteString(p.expr(e.Body))
+		for _, c := range e.Clauses {
+			switch c := c.(type) {
+			case *syntax.ForClause:
+				b.WriteString(" for ")
+				b.WriteString(p.expr(c.Vars))
+				b.WriteString(" in ")
+				b.WriteString(p.expr(c.X))
+			case *syntax.IfClause:
+				b.WriteString(" if ")
+				b.WriteString(p.expr(c.Cond))
+			}
+		}
+		b.WriteString(closeBracket)
+		return b.String()
+	}
+	return ""
+}
+
+// exprList renders ordinary expression elements (list/tuple/dict-entry
+// items), joined with ", ".
+func (p *formatPrinter) exprList(list []syntax.Expr) string {
+	parts := make([]string, len(list))
+	for i, e := range list {
+		parts[i] = p.expr(e)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// argList renders call arguments and def/lambda parameters, which need
+// tight "name=value" spacing for keyword args and defaults (a BinaryExpr
+// with Op==EQ, reused by the parser for this) instead of exprList's normal
+// " = " assignment spacing.
+func (p *formatPrinter) argList(list []syntax.Expr) string {
+	parts := make([]string, len(list))
+	for i, e := range list {
+		if b, ok := e.(*syntax.BinaryExpr); ok && b.Op == syntax.EQ {
+			parts[i] = p.expr(b.X) + "=" + p.expr(b.Y)
+			continue
+		}
+		parts[i] = p.expr(e)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteString renders s as a double-quoted Starlark string literal, for
+// the load() module-side names that don't keep their own Raw text (see
+// LoadStmt's doc comment: they're synthesized identifiers, not literals).
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const formatStarlarkName = "format-starlark"
+
+const formatStarlarkDescription = "Parses a Starlark program and returns it re-rendered with normalized " +
+	"indentation and spacing (buildifier-style), without executing it. Useful for cleaning up a program " +
+	"assembled from fragments, or for keeping a script readable before it's stored. Doesn't preserve " +
+	"comments or reflow long lines."
+
+func addFormatStarlarkTool(server *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        formatStarlarkName,
+		Description: formatStarlarkDescription,
+	}
+	mcp.AddTool(server, tool, handleFormatStarlarkTool)
+}
+
+type formatStarlarkParams struct {
+	Program string `json:"program" jsonschema:"a valid Starlark program to format"`
+}
+
+// handleFormatStarlarkTool recovers from panics in the formatting path,
+// mirroring the other LLM-facing tools in this package.
+func handleFormatStarlarkTool(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args formatStarlarkParams,
+) (result *mcp.CallToolResult, out struct{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("panic during formatting: %v", r)
+		}
+	}()
+
+	f, parseErr := fileOptions().Parse("LLM supplied program", args.Program, 0)
+	if parseErr != nil {
+		return nil, out, fmt.Errorf("failed to format program: %v", enrichExecutionError(parseErr, args.Program))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: formatStarlarkSource(f)},
+		},
+	}, out, nil
+}
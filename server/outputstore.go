@@ -0,0 +1,76 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxStoredOutputs bounds how many past execution outputs are retained per
+// session for pagination/resource lookup, so memory use can't grow unbounded
+// within a single session.
+const maxStoredOutputs = 32
+
+// outputStore retains each session's most recent execute-starlark outputs in
+// a ring buffer, keyed by the MCP session that produced them and then by a
+// monotonically increasing id, so follow-up calls (read-output, and the
+// starlark://session/outputs/{id} resource) can reference a result without
+// the client replaying it back into the prompt. Scoping by session, the way
+// sessionGlobalsStore already does, keeps one SSE client from reading or
+// evicting another concurrently-connected client's stored output by
+// guessing or incrementing an id. The outer map is bounded the same way
+// sessionGlobalsStore bounds its own session map, by evicting the oldest
+// session once more than maxPersistedSessions have ever stored an output, so
+// a long-running serve -transport sse process can't accumulate one entry
+// per session forever either.
+type outputStore struct {
+	mu           sync.Mutex
+	nextID       int
+	outputs      map[*mcp.ServerSession]map[int]string
+	order        map[*mcp.ServerSession][]int // insertion order per session, oldest first, for eviction
+	sessionOrder []*mcp.ServerSession         // insertion order of sessions, oldest first, for eviction
+}
+
+var sessionOutputs = newOutputStore()
+
+func newOutputStore() *outputStore {
+	return &outputStore{
+		outputs: make(map[*mcp.ServerSession]map[int]string),
+		order:   make(map[*mcp.ServerSession][]int),
+	}
+}
+
+// add stores output under session and returns the id it was assigned.
+func (s *outputStore) add(session *mcp.ServerSession, output string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, tracked := s.outputs[session]; !tracked {
+		s.outputs[session] = make(map[int]string)
+		s.sessionOrder = append(s.sessionOrder, session)
+		if len(s.sessionOrder) > maxPersistedSessions {
+			oldest := s.sessionOrder[0]
+			delete(s.outputs, oldest)
+			delete(s.order, oldest)
+			s.sessionOrder = s.sessionOrder[1:]
+		}
+	}
+
+	s.nextID++
+	id := s.nextID
+	s.outputs[session][id] = output
+	s.order[session] = append(s.order[session], id)
+	if order := s.order[session]; len(order) > maxStoredOutputs {
+		delete(s.outputs[session], order[0])
+		s.order[session] = order[1:]
+	}
+	return id
+}
+
+// get returns the output stored under session with the given id, if any.
+func (s *outputStore) get(session *mcp.ServerSession, id int) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	output, ok := s.outputs[session][id]
+	return output, ok
+}
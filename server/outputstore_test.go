@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestOutputStore_EvictsOldestSessionPastCap(t *testing.T) {
+	store := newOutputStore()
+
+	sessions := make([]*mcp.ServerSession, maxPersistedSessions+1)
+	ids := make([]int, len(sessions))
+	for i := range sessions {
+		sessions[i] = &mcp.ServerSession{}
+		ids[i] = store.add(sessions[i], "output")
+	}
+
+	if _, ok := store.get(sessions[0], ids[0]); ok {
+		t.Fatal("oldest session's output was not evicted")
+	}
+	if _, ok := store.get(sessions[len(sessions)-1], ids[len(ids)-1]); !ok {
+		t.Fatal("most recent session's output was evicted")
+	}
+	if len(store.outputs) != maxPersistedSessions {
+		t.Fatalf("store has %d sessions, want %d", len(store.outputs), maxPersistedSessions)
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const readOutputName = "read-output"
+
+const readOutputDescription = "Reads a page of a previously stored execute-starlark output by " +
+	"output_id, for outputs too large to comfortably replay in full. Returns next_offset to pass " +
+	"back in to continue reading, or -1 once the end is reached."
+
+// readOutputPageSize bounds how many bytes of a stored output read-output
+// returns per call.
+const readOutputPageSize = 4 * 1024
+
+func addReadOutputTool(server *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        readOutputName,
+		Description: readOutputDescription,
+	}
+	mcp.AddTool(server, tool, handleReadOutputTool)
+}
+
+type readOutputParams struct {
+	OutputID int `json:"output_id" jsonschema:"the output_id returned by execute-starlark"`
+	Offset   int `json:"offset,omitempty" jsonschema:"byte offset to resume reading from; omit to read from the start"`
+}
+
+type readOutputResult struct {
+	Text       string `json:"text"`
+	NextOffset int    `json:"next_offset" jsonschema:"offset to pass as the next call's offset, or -1 if this was the last page"`
+}
+
+func handleReadOutputTool(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args readOutputParams,
+) (*mcp.CallToolResult, readOutputResult, error) {
+	output, ok := sessionOutputs.get(req.Session, args.OutputID)
+	if !ok {
+		return nil, readOutputResult{}, fmt.Errorf("no stored output with id %d", args.OutputID)
+	}
+	if args.Offset < 0 || args.Offset > len(output) {
+		return nil, readOutputResult{}, fmt.Errorf("offset %d out of range for output of length %d bytes", args.Offset, len(output))
+	}
+
+	end := min(args.Offset+readOutputPageSize, len(output))
+	nextOffset := end
+	if end == len(output) {
+		nextOffset = -1
+	}
+	return nil, readOutputResult{Text: output[args.Offset:end], NextOffset: nextOffset}, nil
+}
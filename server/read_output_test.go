@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alexmdac/starlark-mcp/mcptest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestReadOutput(t *testing.T) {
+	client := startTestServer(t)
+
+	execParams := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print("hello")`,
+			TimeoutSecs: 30,
+		},
+	}
+	execRes := mcptest.CallTool(t, client, execParams)
+	if execRes.IsError {
+		t.Fatalf("expected execute-starlark to succeed, but it failed. Full result: %#v", execRes)
+	}
+	sc := execRes.StructuredContent.(map[string]any)
+	outputID := int(sc["output_id"].(float64))
+
+	readParams := &mcp.CallToolParams{
+		Name: readOutputName,
+		Arguments: readOutputParams{
+			OutputID: outputID,
+		},
+	}
+	text := mcptest.ExpectCallToolSuccess(t, client, readParams)
+	if text != `{"text":"hello\n","next_offset":-1}` {
+		t.Fatalf("unexpected read-output response: %q", text)
+	}
+}
+
+func TestReadOutput_Pagination(t *testing.T) {
+	client := startTestServer(t)
+
+	// print() adds a trailing newline, so the stored output ends up
+	// readOutputPageSize+10 bytes long, matching the assertions below.
+	execRes := mcptest.CallTool(t, client, &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     fmt.Sprintf(`print("x" * %d)`, readOutputPageSize+9),
+			TimeoutSecs: 30,
+		},
+	})
+	if execRes.IsError {
+		t.Fatalf("expected execute-starlark to succeed, but it failed. Full result: %#v", execRes)
+	}
+	execSC := execRes.StructuredContent.(map[string]any)
+	id := int(execSC["output_id"].(float64))
+
+	first := mcptest.CallTool(t, client, &mcp.CallToolParams{
+		Name: readOutputName,
+		Arguments: readOutputParams{
+			OutputID: id,
+		},
+	})
+	firstSC := first.StructuredContent.(map[string]any)
+	if len(firstSC["text"].(string)) != readOutputPageSize {
+		t.Fatalf("got first page of %d bytes, want %d", len(firstSC["text"].(string)), readOutputPageSize)
+	}
+	nextOffset := int(firstSC["next_offset"].(float64))
+	if nextOffset != readOutputPageSize {
+		t.Fatalf("next_offset = %d, want %d", nextOffset, readOutputPageSize)
+	}
+
+	second := mcptest.CallTool(t, client, &mcp.CallToolParams{
+		Name: readOutputName,
+		Arguments: readOutputParams{
+			OutputID: id,
+			Offset:   nextOffset,
+		},
+	})
+	secondSC := second.StructuredContent.(map[string]any)
+	if len(secondSC["text"].(string)) != 10 {
+		t.Fatalf("got second page of %d bytes, want 10", len(secondSC["text"].(string)))
+	}
+	if int(secondSC["next_offset"].(float64)) != -1 {
+		t.Fatalf("next_offset = %v, want -1", secondSC["next_offset"])
+	}
+}
+
+func TestReadOutput_ScopedToSession(t *testing.T) {
+	owner := startTestServer(t)
+	other := startTestServer(t)
+
+	execRes := mcptest.CallTool(t, owner, &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print("secret")`,
+			TimeoutSecs: 30,
+		},
+	})
+	if execRes.IsError {
+		t.Fatalf("expected execute-starlark to succeed, but it failed. Full result: %#v", execRes)
+	}
+	sc := execRes.StructuredContent.(map[string]any)
+	outputID := int(sc["output_id"].(float64))
+
+	params := &mcp.CallToolParams{
+		Name: readOutputName,
+		Arguments: readOutputParams{
+			OutputID: outputID,
+		},
+	}
+	errorText := mcptest.ExpectCallToolError(t, other, params)
+	if errorText == "" {
+		t.Fatal("expected an error reading another session's output id")
+	}
+}
+
+func TestReadOutput_UnknownID(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: readOutputName,
+		Arguments: readOutputParams{
+			OutputID: -1,
+		},
+	}
+	errorText := mcptest.ExpectCallToolError(t, client, params)
+	if errorText == "" {
+		t.Fatal("expected an error for an unknown output id")
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	starlarkjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+)
+
+// maxSafeIntegerMagnitude is the largest integer magnitude that survives
+// a round trip through float64 without losing precision (2^53).
+var maxSafeIntegerMagnitude = new(big.Int).Lsh(big.NewInt(1), 53)
+
+// encodeGlobals serializes the named top-level variables from globals to
+// JSON, for execute-starlark's return_globals parameter: a program that
+// ends with a data structure can have it handed back structured instead of
+// forcing everything through print(), which loses the structure. Reuses
+// go.starlark.net's own json.encode rather than hand-rolling a second
+// Starlark-to-JSON converter alongside the one in starlark_ast.go, since
+// that converter renders syntax tree nodes, not runtime values. The result
+// is decoded back into plain Go values (rather than kept as json.RawMessage)
+// since the MCP go-sdk's schema validator expects structured output fields
+// it can introspect, not opaque byte strings.
+func encodeGlobals(globals starlark.StringDict, names []string) (map[string]any, error) {
+	encode := starlarkjson.Module.Members["encode"]
+	thread := &starlark.Thread{}
+
+	out := make(map[string]any, len(names))
+	for _, name := range names {
+		val, ok := globals[name]
+		if !ok {
+			return nil, fmt.Errorf("no such global: %q", name)
+		}
+		encoded, err := starlark.Call(thread, encode, starlark.Tuple{val}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode global %q: %v", name, err)
+		}
+		str, ok := starlark.AsString(encoded)
+		if !ok {
+			return nil, fmt.Errorf("failed to encode global %q: json.encode returned a non-string", name)
+		}
+		// Decoding with UseNumber, rather than straight into any, keeps the
+		// exact digits of a large Starlark int around long enough for
+		// normalizeNumbers to rescue them below: the MCP go-sdk's own
+		// output-schema validation unmarshals our marshaled result a second
+		// time without UseNumber, so by the time it's done every JSON number
+		// has already been rounded through float64, however it got there.
+		dec := json.NewDecoder(bytes.NewReader([]byte(str)))
+		dec.UseNumber()
+		var decoded any
+		if err := dec.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode global %q: %v", name, err)
+		}
+		out[name] = normalizeNumbers(decoded)
+	}
+	return out, nil
+}
+
+// normalizeNumbers walks a value decoded with json.Decoder.UseNumber,
+// converting each json.Number to a float64 (the MCP go-sdk's own schema
+// validation would do this anyway) unless it's an integer too large to
+// survive that conversion, in which case it's converted to its exact
+// decimal string instead — the same strategy literalValueToJSON in
+// starlark_ast.go uses for a *big.Int.
+func normalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		return normalizeNumber(v)
+	case map[string]any:
+		for k, elem := range v {
+			v[k] = normalizeNumbers(elem)
+		}
+		return v
+	case []any:
+		for i, elem := range v {
+			v[i] = normalizeNumbers(elem)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func normalizeNumber(n json.Number) any {
+	if !strings.ContainsAny(string(n), ".eE") {
+		if i, ok := new(big.Int).SetString(string(n), 10); ok && i.CmpAbs(maxSafeIntegerMagnitude) > 0 {
+			return i.String()
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return string(n) // unreachable: n came from a valid JSON number
+	}
+	return f
+}
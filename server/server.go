@@ -4,9 +4,19 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// New creates a configured MCP server with all tools and resources registered.
-func New() *mcp.Server {
-	s := mcp.NewServer(&mcp.Implementation{Name: "starlark-mcp"}, nil)
+// New creates a configured MCP server with all tools and resources
+// registered. version is reported to clients via the Implementation struct.
+func New(version string) *mcp.Server {
+	s := mcp.NewServer(&mcp.Implementation{Name: "starlark-mcp", Version: version}, nil)
 	addExecuteStarlarkTool(s)
+	addEvalStarlarkExpressionTool(s)
+	addCheckStarlarkTool(s)
+	addFormatStarlarkTool(s)
+	addStarlarkASTTool(s)
+	addDescribeDialectTool(s)
+	addReadOutputTool(s)
+	addCookbookResources(s)
+	addDocResources(s)
+	addSessionOutputsResource(s)
 	return s
 }
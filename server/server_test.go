@@ -4,77 +4,137 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/alexmdac/starlark-mcp/mcptest"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func startTestServer(t *testing.T) *mcp.ClientSession {
 	t.Helper()
+	return mcptest.NewClient(t, New("test"))
+}
 
-	t1, t2 := mcp.NewInMemoryTransports()
-	s := New()
-	client := mcp.NewClient(&mcp.Implementation{Name: "test client"}, nil)
-
-	serverSession, err := s.Connect(t.Context(), t1, nil)
-	if err != nil {
-		t.Fatalf("Failed to connect server: %v", err)
+func TestExecuteStarlark(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print("Hello, world!")`,
+			TimeoutSecs: 30,
+		},
 	}
-	clientSession, err := client.Connect(t.Context(), t2, nil)
-	if err != nil {
-		t.Fatalf("Failed to connect client: %v", err)
+	text := mcptest.ExpectCallToolSuccess(t, client, params)
+	expected := "Hello, world!\n"
+	if text != expected {
+		t.Fatalf("Incorrect response text:\n- want: %q\n-  got: %q", expected, text)
 	}
+}
 
-	t.Cleanup(func() {
-		if err := clientSession.Close(); err != nil {
-			t.Fatalf("Failed to close client session: %v", err)
-		}
-		if err := serverSession.Wait(); err != nil {
-			t.Fatalf("Server session failed: %v", err)
-		}
-	})
+func TestExecuteStarlark_Timeout(t *testing.T) {
+	client := startTestServer(t)
+	program := `
+def main():
+  for i in range(10000000): pass
+main()`
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     program,
+			TimeoutSecs: 0.1, // A very short timeout
+		},
+	}
 
-	return clientSession
+	errorText := mcptest.ExpectCallToolError(t, client, params)
+	if !strings.Contains(errorText, "context deadline exceeded") {
+		t.Fatalf("expected error to contain %q, but got %q", "context deadline exceeded",
+			errorText)
+	}
 }
 
-func expectCallToolSuccess(t *testing.T, client *mcp.ClientSession, params *mcp.CallToolParams) string {
-	t.Helper()
-	res := callTool(t, client, params)
-	if res.IsError {
-		t.Fatalf("Expected tool call to succeed, but it failed. Full result: %#v", res)
+func TestExecuteStarlark_InvalidTimeout(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     "print(1)",
+			TimeoutSecs: -1.0, // Invalid timeout
+		},
 	}
-	return expectTextContent(t, res)
-}
 
-func expectCallToolError(t *testing.T, client *mcp.ClientSession, params *mcp.CallToolParams) string {
-	t.Helper()
-	res := callTool(t, client, params)
-	if !res.IsError {
-		t.Fatal("expected an error, but got none")
+	errorText := mcptest.ExpectCallToolError(t, client, params)
+	if !strings.Contains(errorText, "invalid timeout") {
+		t.Fatalf("expected error to contain %q, but got %q", "invalid timeout",
+			errorText)
 	}
-	return expectTextContent(t, res)
 }
 
-func callTool(t *testing.T, client *mcp.ClientSession, params *mcp.CallToolParams) *mcp.CallToolResult {
-	t.Helper()
-	res, err := client.CallTool(t.Context(), params)
-	if err != nil {
-		t.Fatalf("client.CallTool failed: %v", err)
+func TestExecuteStarlark_CPULimit(t *testing.T) {
+	client := startTestServer(t)
+	// A generous wall-clock timeout, so a CPU-bound infinite loop should
+	// hit the step limit rather than the timeout.
+	program := `
+def main():
+    i = 0
+    while True:
+        i += 1
+main()`
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     program,
+			TimeoutSecs: 60,
+		},
+	}
+
+	errorText := mcptest.ExpectCallToolError(t, client, params)
+	if !strings.Contains(errorText, "too many steps") {
+		t.Fatalf("expected error to contain %q, but got %q", "too many steps", errorText)
 	}
-	return res
 }
 
-func expectTextContent(t *testing.T, res *mcp.CallToolResult) string {
-	t.Helper()
-	if len(res.Content) != 1 {
-		t.Fatalf("Incorrect number of content blocks:\n- want: 1\n-  got: %d", len(res.Content))
+func TestExecuteStarlark_Warnings(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `len = 5`,
+			TimeoutSecs: 30,
+		},
+	}
+
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
 	}
-	textContent, ok := res.Content[0].(*mcp.TextContent)
+	sc, ok := res.StructuredContent.(map[string]any)
 	if !ok {
-		t.Fatalf("Incorrect content block type:\n- want: *mcp.TextContent\n-  got: %T", res.Content[0])
+		t.Fatalf("expected StructuredContent to be a map, got %#v", res.StructuredContent)
+	}
+	warnings, _ := sc["warnings"].([]any)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].(string), `"len" shadows a builtin`) {
+		t.Fatalf("unexpected warnings: %#v", sc["warnings"])
 	}
-	return textContent.Text
 }
 
-func TestExecuteStarlark(t *testing.T) {
+func TestExecuteStarlarkToolDescription_Compact(t *testing.T) {
+	t.Setenv(docsModeEnvVar, compactDocsMode)
+
+	got := executeStarlarkToolDescription()
+	if len(got) >= len(executeStarlarkDescription) {
+		t.Fatalf("expected the compact description to be shorter than the full one (%d bytes); got %d bytes: %q",
+			len(executeStarlarkDescription), len(got), got)
+	}
+	if !strings.Contains(got, "math") {
+		t.Fatalf("expected the compact description to still list the math module, got %q", got)
+	}
+}
+
+func TestExecuteStarlarkToolDescription_Default(t *testing.T) {
+	if got := executeStarlarkToolDescription(); got != executeStarlarkDescription {
+		t.Fatalf("expected the full description.md text by default, got %q", got)
+	}
+}
+
+func TestExecuteStarlark_StructuredContent(t *testing.T) {
 	client := startTestServer(t)
 	params := &mcp.CallToolParams{
 		Name: executeStarlarkName,
@@ -83,48 +143,201 @@ func TestExecuteStarlark(t *testing.T) {
 			TimeoutSecs: 30,
 		},
 	}
-	text := expectCallToolSuccess(t, client, params)
-	expected := "Hello, world!\n"
-	if text != expected {
-		t.Fatalf("Incorrect response text:\n- want: %q\n-  got: %q", expected, text)
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("expected StructuredContent to be a map, got %#v", res.StructuredContent)
+	}
+	if sc["stdout"] != "Hello, world!\n" {
+		t.Fatalf("unexpected stdout: %#v", sc["stdout"])
+	}
+	if sc["truncated"] != false {
+		t.Fatalf("expected truncated to be false, got %#v", sc["truncated"])
+	}
+	if steps, ok := sc["steps"].(float64); !ok || steps <= 0 {
+		t.Fatalf("expected a positive step count, got %#v", sc["steps"])
+	}
+	if _, ok := sc["duration_ms"].(float64); !ok {
+		t.Fatalf("expected duration_ms to be a number, got %#v", sc["duration_ms"])
 	}
 }
 
-func TestExecuteStarlark_Timeout(t *testing.T) {
+func TestExecuteStarlark_StdoutTruncation(t *testing.T) {
 	client := startTestServer(t)
+	// Prints more than maxStructuredStdoutLen bytes but comfortably under
+	// maxOutputLen, so the stdout field should be cut short while the full
+	// text content and read-output's copy stay intact.
 	program := `
 def main():
-  for i in range(10000000): pass
-main()`
+    for i in range(80):
+        print("X" * 100)
+main()
+`
 	params := &mcp.CallToolParams{
 		Name: executeStarlarkName,
 		Arguments: executeStarlarkParams{
 			Program:     program,
-			TimeoutSecs: 0.1, // A very short timeout
+			TimeoutSecs: 30,
 		},
 	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	stdout, _ := sc["stdout"].(string)
+	if len(stdout) != maxStructuredStdoutLen {
+		t.Fatalf("expected stdout to be capped at %d bytes, got %d", maxStructuredStdoutLen, len(stdout))
+	}
+	if sc["truncated"] != true {
+		t.Fatalf("expected truncated to be true, got %#v", sc["truncated"])
+	}
+}
 
-	errorText := expectCallToolError(t, client, params)
-	if !strings.Contains(errorText, "context deadline exceeded") {
-		t.Fatalf("expected error to contain %q, but got %q", "context deadline exceeded",
-			errorText)
+func TestExecuteStarlark_ReturnGlobals(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:       `result = {"total": 3, "items": [1, 2]}`,
+			TimeoutSecs:   30,
+			ReturnGlobals: []string{"result"},
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	globals, ok := sc["globals"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected globals to be a map, got %#v", sc["globals"])
+	}
+	result, ok := globals["result"].(map[string]any)
+	if !ok || result["total"] != float64(3) {
+		t.Fatalf("unexpected result global: %#v", globals["result"])
 	}
 }
 
-func TestExecuteStarlark_InvalidTimeout(t *testing.T) {
+func TestExecuteStarlark_ReturnGlobals_LargeInt(t *testing.T) {
 	client := startTestServer(t)
 	params := &mcp.CallToolParams{
 		Name: executeStarlarkName,
 		Arguments: executeStarlarkParams{
-			Program:     "print(1)",
-			TimeoutSecs: -1.0, // Invalid timeout
+			Program:       `big = 123456789012345678901234567890`,
+			TimeoutSecs:   30,
+			ReturnGlobals: []string{"big"},
 		},
 	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	globals := sc["globals"].(map[string]any)
+	if globals["big"] != "123456789012345678901234567890" {
+		t.Fatalf("big int lost precision: got %#v", globals["big"])
+	}
+}
 
-	errorText := expectCallToolError(t, client, params)
-	if !strings.Contains(errorText, "invalid timeout") {
-		t.Fatalf("expected error to contain %q, but got %q", "invalid timeout",
-			errorText)
+func TestExecuteStarlark_ReturnGlobals_Undefined(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:       `x = 1`,
+			TimeoutSecs:   30,
+			ReturnGlobals: []string{"missing"},
+		},
+	}
+	errorText := mcptest.ExpectCallToolError(t, client, params)
+	if !strings.Contains(errorText, `no such global: "missing"`) {
+		t.Fatalf("unexpected error: %q", errorText)
+	}
+}
+
+func TestExecuteStarlark_Persist(t *testing.T) {
+	client := startTestServer(t)
+
+	first := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `x = 1`,
+			TimeoutSecs: 30,
+			Persist:     true,
+		},
+	}
+	mcptest.ExpectCallToolSuccess(t, client, first)
+
+	second := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print(x + 1)`,
+			TimeoutSecs: 30,
+			Persist:     true,
+		},
+	}
+	text := mcptest.ExpectCallToolSuccess(t, client, second)
+	if text != "2\n" {
+		t.Fatalf("expected persisted global x to be visible, got %q", text)
+	}
+}
+
+func TestExecuteStarlark_PersistRequiresOptIn(t *testing.T) {
+	client := startTestServer(t)
+
+	first := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `x = 1`,
+			TimeoutSecs: 30,
+			Persist:     true,
+		},
+	}
+	mcptest.ExpectCallToolSuccess(t, client, first)
+
+	second := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print(x + 1)`,
+			TimeoutSecs: 30,
+			// Persist omitted: x should not carry over.
+		},
+	}
+	errorText := mcptest.ExpectCallToolError(t, client, second)
+	if !strings.Contains(errorText, "undefined: x") {
+		t.Fatalf("expected error to contain %q, but got %q", "undefined: x", errorText)
+	}
+}
+
+func TestExecuteStarlark_ResetSession(t *testing.T) {
+	client := startTestServer(t)
+
+	first := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `x = 1`,
+			TimeoutSecs: 30,
+			Persist:     true,
+		},
+	}
+	mcptest.ExpectCallToolSuccess(t, client, first)
+
+	second := &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:      `print(x + 1)`,
+			TimeoutSecs:  30,
+			Persist:      true,
+			ResetSession: true,
+		},
+	}
+	errorText := mcptest.ExpectCallToolError(t, client, second)
+	if !strings.Contains(errorText, "undefined: x") {
+		t.Fatalf("expected error to contain %q, but got %q", "undefined: x", errorText)
 	}
 }
 
@@ -149,7 +362,7 @@ main()
 		},
 	}
 
-	errorText := expectCallToolError(t, client, params)
+	errorText := mcptest.ExpectCallToolError(t, client, params)
 	wantErrorText := "output length 16400 bytes exceeded 16384 bytes"
 	if !strings.Contains(errorText, wantErrorText) {
 		t.Fatalf("expected error to contain %q, but got %q", wantErrorText,
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.starlark.net/starlark"
+)
+
+// maxPersistedSessions bounds how many sessions' globals are retained at
+// once, the same way outputStore bounds its stored outputs, so a
+// long-running server (e.g. serve -transport sse) can't accumulate one
+// entry per session forever. The go-sdk doesn't expose a session-close
+// hook to evict on disconnect, so this evicts the oldest session instead.
+const maxPersistedSessions = 256
+
+// sessionGlobalsStore retains the top-level bindings left behind by an
+// execute-starlark call that opted into persist, keyed by the MCP session
+// that made it, so a later call on the same session can build on them
+// instead of redefining everything from scratch. Keying on the
+// *mcp.ServerSession pointer itself (rather than ID(), which is empty for
+// the stdio and SSE transports) works across every transport this server
+// supports.
+type sessionGlobalsStore struct {
+	mu      sync.Mutex
+	globals map[*mcp.ServerSession]starlark.StringDict
+	order   []*mcp.ServerSession // insertion order, oldest first, for eviction
+}
+
+var persistedGlobals = newSessionGlobalsStore()
+
+func newSessionGlobalsStore() *sessionGlobalsStore {
+	return &sessionGlobalsStore{globals: make(map[*mcp.ServerSession]starlark.StringDict)}
+}
+
+// get returns the globals persisted for session, or nil if none are stored.
+func (s *sessionGlobalsStore) get(session *mcp.ServerSession) starlark.StringDict {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.globals[session]
+}
+
+// set replaces the globals persisted for session.
+func (s *sessionGlobalsStore) set(session *mcp.ServerSession, globals starlark.StringDict) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.globals[session]; !exists {
+		s.order = append(s.order, session)
+		if len(s.order) > maxPersistedSessions {
+			delete(s.globals, s.order[0])
+			s.order = s.order[1:]
+		}
+	}
+	s.globals[session] = globals
+}
+
+// reset discards any globals persisted for session.
+func (s *sessionGlobalsStore) reset(session *mcp.ServerSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.globals, session)
+	for i, sess := range s.order {
+		if sess == session {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
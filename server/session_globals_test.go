@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.starlark.net/starlark"
+)
+
+func TestSessionGlobalsStore_EvictsOldestSessionPastCap(t *testing.T) {
+	store := newSessionGlobalsStore()
+
+	sessions := make([]*mcp.ServerSession, maxPersistedSessions+1)
+	for i := range sessions {
+		sessions[i] = &mcp.ServerSession{}
+		store.set(sessions[i], starlark.StringDict{"x": starlark.MakeInt(i)})
+	}
+
+	if got := store.get(sessions[0]); got != nil {
+		t.Fatalf("oldest session's globals were not evicted: %v", got)
+	}
+	if got := store.get(sessions[len(sessions)-1]); got == nil {
+		t.Fatal("most recent session's globals were evicted")
+	}
+	if len(store.globals) != maxPersistedSessions {
+		t.Fatalf("store has %d entries, want %d", len(store.globals), maxPersistedSessions)
+	}
+}
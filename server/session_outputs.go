@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const sessionOutputsURITemplate = "starlark://session/outputs/{id}"
+const sessionOutputsURIPrefix = "starlark://session/outputs/"
+
+// addSessionOutputsResource registers a starlark://session/outputs/{id}
+// resource template over the same outputStore that backs the read-output
+// tool, so an agent can reference an earlier execute-starlark result by
+// URI instead of replaying its text back into the prompt.
+func addSessionOutputsResource(s *mcp.Server) {
+	s.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: sessionOutputsURITemplate,
+		Name:        "session-output",
+		Description: "A previous execute-starlark output, by the output_id it returned",
+		MIMEType:    "text/plain",
+	}, handleSessionOutput)
+}
+
+func handleSessionOutput(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	id, err := strconv.Atoi(strings.TrimPrefix(uri, sessionOutputsURIPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid session output URI %q: %v", uri, err)
+	}
+
+	output, ok := sessionOutputs.get(req.Session, id)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: "text/plain",
+				Text:     output,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alexmdac/starlark-mcp/mcptest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSessionOutputsResource(t *testing.T) {
+	client := startTestServer(t)
+
+	execRes := mcptest.CallTool(t, client, &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print("hello from a previous execution")`,
+			TimeoutSecs: 30,
+		},
+	})
+	if execRes.IsError {
+		t.Fatalf("expected execute-starlark to succeed, but it failed. Full result: %#v", execRes)
+	}
+	sc := execRes.StructuredContent.(map[string]any)
+	id := int(sc["output_id"].(float64))
+
+	got, err := client.ReadResource(t.Context(), &mcp.ReadResourceParams{
+		URI: fmt.Sprintf("starlark://session/outputs/%d", id),
+	})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(got.Contents) != 1 || got.Contents[0].Text != "hello from a previous execution\n" {
+		t.Fatalf("unexpected contents: %#v", got.Contents)
+	}
+}
+
+func TestSessionOutputsResource_ScopedToSession(t *testing.T) {
+	owner := startTestServer(t)
+	other := startTestServer(t)
+
+	execRes := mcptest.CallTool(t, owner, &mcp.CallToolParams{
+		Name: executeStarlarkName,
+		Arguments: executeStarlarkParams{
+			Program:     `print("secret")`,
+			TimeoutSecs: 30,
+		},
+	})
+	if execRes.IsError {
+		t.Fatalf("expected execute-starlark to succeed, but it failed. Full result: %#v", execRes)
+	}
+	sc := execRes.StructuredContent.(map[string]any)
+	id := int(sc["output_id"].(float64))
+
+	_, err := other.ReadResource(t.Context(), &mcp.ReadResourceParams{
+		URI: fmt.Sprintf("starlark://session/outputs/%d", id),
+	})
+	if err == nil {
+		t.Fatal("expected an error reading another session's output via its id")
+	}
+}
+
+func TestSessionOutputsResource_Unknown(t *testing.T) {
+	client := startTestServer(t)
+	_, err := client.ReadResource(t.Context(), &mcp.ReadResourceParams{
+		URI: "starlark://session/outputs/999999",
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unknown output id")
+	}
+}
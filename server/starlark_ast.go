@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.starlark.net/syntax"
+)
+
+const starlarkASTName = "starlark-ast"
+
+const starlarkASTDescription = "Parses a Starlark program and returns its abstract syntax tree as JSON " +
+	"(node kinds, 1-based line/column positions, and literal values), without executing it. Useful for " +
+	"building analysis or refactoring tooling on top of a program without reimplementing a Starlark parser."
+
+func addStarlarkASTTool(server *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        starlarkASTName,
+		Description: starlarkASTDescription,
+	}
+	mcp.AddTool(server, tool, handleStarlarkASTTool)
+}
+
+type starlarkASTParams struct {
+	Program string `json:"program" jsonschema:"a valid Starlark program to parse"`
+}
+
+type starlarkASTResult struct {
+	AST map[string]any `json:"ast" jsonschema:"the program's syntax tree, rooted at a \"File\" node"`
+}
+
+// handleStarlarkASTTool recovers from panics, mirroring the other
+// parse-only tools in this package.
+func handleStarlarkASTTool(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	args starlarkASTParams,
+) (result *mcp.CallToolResult, out starlarkASTResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, out, err = nil, starlarkASTResult{}, fmt.Errorf("panic during parse: %v", r)
+		}
+	}()
+
+	f, parseErr := fileOptions().Parse("LLM supplied program", args.Program, 0)
+	if parseErr != nil {
+		return nil, starlarkASTResult{}, fmt.Errorf("failed to parse program: %v", enrichExecutionError(parseErr, args.Program))
+	}
+
+	ast := fileToJSON(f)
+	return nil, starlarkASTResult{AST: ast}, nil
+}
+
+// fileToJSON converts a parsed file into the same generic node shape
+// nodeToJSON uses for every other node, since *syntax.File isn't itself a
+// syntax.Stmt or syntax.Expr and so needs its own entry point.
+func fileToJSON(f *syntax.File) map[string]any {
+	return map[string]any{
+		"kind":  "File",
+		"stmts": stmtsToJSON(f.Stmts),
+	}
+}
+
+func stmtsToJSON(stmts []syntax.Stmt) []any {
+	out := make([]any, len(stmts))
+	for i, s := range stmts {
+		out[i] = nodeToJSON(s)
+	}
+	return out
+}
+
+func exprsToJSON(exprs []syntax.Expr) []any {
+	out := make([]any, len(exprs))
+	for i, e := range exprs {
+		out[i] = nodeToJSON(e)
+	}
+	return out
+}
+
+// literalValueToJSON renders a Literal's Value (string | int64 | *big.Int |
+// float64, per its doc comment) so a large int survives round-tripping
+// through the MCP framework's own JSON encoding without silently losing
+// precision to a float64 conversion; a *big.Int is converted to its exact
+// decimal string instead of being passed through as-is. Raw already carries
+// the untouched source text for a caller that needs that either way.
+func literalValueToJSON(v any) any {
+	if bi, ok := v.(*big.Int); ok {
+		return bi.String()
+	}
+	return v
+}
+
+// withPos adds 1-based start/end line and column fields to a node's JSON
+// object, positioned the same way check-starlark's diagnostics are.
+func withPos(n syntax.Node, fields map[string]any) map[string]any {
+	start, end := n.Span()
+	fields["pos"] = map[string]any{"line": start.Line, "col": start.Col}
+	fields["end"] = map[string]any{"line": end.Line, "col": end.Col}
+	return fields
+}
+
+// nodeToJSON converts one statement or expression node, and everything
+// beneath it, into a JSON-serializable map keyed by "kind" plus whatever
+// fields that kind needs. An unrecognized node (there shouldn't be one,
+// since this switch covers every type the parser produces) becomes a
+// bare {"kind": "Unknown"} rather than a panic, so a future upstream
+// syntax addition degrades gracefully instead of crashing the tool.
+func nodeToJSON(n syntax.Node) map[string]any {
+	switch n := n.(type) {
+	case *syntax.AssignStmt:
+		return withPos(n, map[string]any{
+			"kind": "AssignStmt",
+			"op":   n.Op.String(),
+			"lhs":  nodeToJSON(n.LHS),
+			"rhs":  nodeToJSON(n.RHS),
+		})
+
+	case *syntax.ExprStmt:
+		return withPos(n, map[string]any{
+			"kind": "ExprStmt",
+			"x":    nodeToJSON(n.X),
+		})
+
+	case *syntax.DefStmt:
+		return withPos(n, map[string]any{
+			"kind":   "DefStmt",
+			"name":   n.Name.Name,
+			"params": exprsToJSON(n.Params),
+			"body":   stmtsToJSON(n.Body),
+		})
+
+	case *syntax.IfStmt:
+		return withPos(n, map[string]any{
+			"kind":  "IfStmt",
+			"cond":  nodeToJSON(n.Cond),
+			"true":  stmtsToJSON(n.True),
+			"false": stmtsToJSON(n.False),
+		})
+
+	case *syntax.ForStmt:
+		return withPos(n, map[string]any{
+			"kind": "ForStmt",
+			"vars": nodeToJSON(n.Vars),
+			"x":    nodeToJSON(n.X),
+			"body": stmtsToJSON(n.Body),
+		})
+
+	case *syntax.WhileStmt:
+		return withPos(n, map[string]any{
+			"kind": "WhileStmt",
+			"cond": nodeToJSON(n.Cond),
+			"body": stmtsToJSON(n.Body),
+		})
+
+	case *syntax.LoadStmt:
+		bindings := make([]any, len(n.From))
+		for i, from := range n.From {
+			bindings[i] = map[string]any{"local": n.To[i].Name, "name": from.Name}
+		}
+		return withPos(n, map[string]any{
+			"kind":     "LoadStmt",
+			"module":   n.Module.Value,
+			"bindings": bindings,
+		})
+
+	case *syntax.BranchStmt:
+		return withPos(n, map[string]any{
+			"kind":  "BranchStmt",
+			"token": n.Token.String(),
+		})
+
+	case *syntax.ReturnStmt:
+		fields := map[string]any{"kind": "ReturnStmt"}
+		if n.Result != nil {
+			fields["result"] = nodeToJSON(n.Result)
+		}
+		return withPos(n, fields)
+
+	case *syntax.Ident:
+		return withPos(n, map[string]any{
+			"kind": "Ident",
+			"name": n.Name,
+		})
+
+	case *syntax.Literal:
+		return withPos(n, map[string]any{
+			"kind":  "Literal",
+			"token": n.Token.String(),
+			"value": literalValueToJSON(n.Value),
+			"raw":   n.Raw,
+		})
+
+	case *syntax.ParenExpr:
+		return withPos(n, map[string]any{
+			"kind": "ParenExpr",
+			"x":    nodeToJSON(n.X),
+		})
+
+	case *syntax.CallExpr:
+		return withPos(n, map[string]any{
+			"kind": "CallExpr",
+			"fn":   nodeToJSON(n.Fn),
+			"args": exprsToJSON(n.Args),
+		})
+
+	case *syntax.DotExpr:
+		return withPos(n, map[string]any{
+			"kind": "DotExpr",
+			"x":    nodeToJSON(n.X),
+			"name": n.Name.Name,
+		})
+
+	case *syntax.IndexExpr:
+		return withPos(n, map[string]any{
+			"kind": "IndexExpr",
+			"x":    nodeToJSON(n.X),
+			"y":    nodeToJSON(n.Y),
+		})
+
+	case *syntax.SliceExpr:
+		fields := map[string]any{"kind": "SliceExpr", "x": nodeToJSON(n.X)}
+		if n.Lo != nil {
+			fields["lo"] = nodeToJSON(n.Lo)
+		}
+		if n.Hi != nil {
+			fields["hi"] = nodeToJSON(n.Hi)
+		}
+		if n.Step != nil {
+			fields["step"] = nodeToJSON(n.Step)
+		}
+		return withPos(n, fields)
+
+	case *syntax.UnaryExpr:
+		fields := map[string]any{"kind": "UnaryExpr", "op": n.Op.String()}
+		if n.X != nil {
+			fields["x"] = nodeToJSON(n.X)
+		}
+		return withPos(n, fields)
+
+	case *syntax.BinaryExpr:
+		return withPos(n, map[string]any{
+			"kind": "BinaryExpr",
+			"op":   n.Op.String(),
+			"x":    nodeToJSON(n.X),
+			"y":    nodeToJSON(n.Y),
+		})
+
+	case *syntax.TupleExpr:
+		return withPos(n, map[string]any{
+			"kind": "TupleExpr",
+			"list": exprsToJSON(n.List),
+		})
+
+	case *syntax.ListExpr:
+		return withPos(n, map[string]any{
+			"kind": "ListExpr",
+			"list": exprsToJSON(n.List),
+		})
+
+	case *syntax.DictExpr:
+		return withPos(n, map[string]any{
+			"kind": "DictExpr",
+			"list": exprsToJSON(n.List),
+		})
+
+	case *syntax.DictEntry:
+		return withPos(n, map[string]any{
+			"kind":  "DictEntry",
+			"key":   nodeToJSON(n.Key),
+			"value": nodeToJSON(n.Value),
+		})
+
+	case *syntax.LambdaExpr:
+		return withPos(n, map[string]any{
+			"kind":   "LambdaExpr",
+			"params": exprsToJSON(n.Params),
+			"body":   nodeToJSON(n.Body),
+		})
+
+	case *syntax.CondExpr:
+		return withPos(n, map[string]any{
+			"kind":  "CondExpr",
+			"cond":  nodeToJSON(n.Cond),
+			"true":  nodeToJSON(n.True),
+			"false": nodeToJSON(n.False),
+		})
+
+	case *syntax.Comprehension:
+		clauses := make([]any, len(n.Clauses))
+		for i, c := range n.Clauses {
+			clauses[i] = nodeToJSON(c)
+		}
+		return withPos(n, map[string]any{
+			"kind":    "Comprehension",
+			"curly":   n.Curly,
+			"body":    nodeToJSON(n.Body),
+			"clauses": clauses,
+		})
+
+	case *syntax.ForClause:
+		return withPos(n, map[string]any{
+			"kind": "ForClause",
+			"vars": nodeToJSON(n.Vars),
+			"x":    nodeToJSON(n.X),
+		})
+
+	case *syntax.IfClause:
+		return withPos(n, map[string]any{
+			"kind": "IfClause",
+			"cond": nodeToJSON(n.Cond),
+		})
+	}
+	return map[string]any{"kind": "Unknown"}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/alexmdac/starlark-mcp/mcptest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestStarlarkAST_Basic(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: starlarkASTName,
+		Arguments: starlarkASTParams{
+			Program: "x = 1 + 2\n",
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("expected StructuredContent to be a map, got %#v", res.StructuredContent)
+	}
+	ast, ok := sc["ast"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected ast to be a map, got %#v", sc["ast"])
+	}
+	if ast["kind"] != "File" {
+		t.Fatalf("expected root kind File, got %#v", ast["kind"])
+	}
+	stmts, ok := ast["stmts"].([]any)
+	if !ok || len(stmts) != 1 {
+		t.Fatalf("expected exactly one top-level statement, got %#v", ast["stmts"])
+	}
+	assign := stmts[0].(map[string]any)
+	if assign["kind"] != "AssignStmt" {
+		t.Fatalf("expected an AssignStmt, got %#v", assign["kind"])
+	}
+	rhs := assign["rhs"].(map[string]any)
+	if rhs["kind"] != "BinaryExpr" || rhs["op"] != "+" {
+		t.Fatalf("expected a '+' BinaryExpr, got %#v", rhs)
+	}
+	pos := assign["pos"].(map[string]any)
+	if pos["line"] != float64(1) || pos["col"] != float64(1) {
+		t.Fatalf("expected the assignment to start at 1:1, got %#v", pos)
+	}
+}
+
+func TestStarlarkAST_SyntaxError(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: starlarkASTName,
+		Arguments: starlarkASTParams{
+			Program: "def f(:\n  pass",
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if !res.IsError {
+		t.Fatalf("expected tool call to fail on invalid syntax, got: %#v", res)
+	}
+}
+
+func TestStarlarkAST_BigIntLiteral(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: starlarkASTName,
+		Arguments: starlarkASTParams{
+			Program: "x = 1000000000000000000000\n",
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	ast := sc["ast"].(map[string]any)
+	assign := ast["stmts"].([]any)[0].(map[string]any)
+	lit := assign["rhs"].(map[string]any)
+	if lit["value"] != "1000000000000000000000" {
+		t.Fatalf("expected the exact decimal string, got %#v", lit["value"])
+	}
+}
+
+func TestStarlarkAST_LoadAliasing(t *testing.T) {
+	client := startTestServer(t)
+	params := &mcp.CallToolParams{
+		Name: starlarkASTName,
+		Arguments: starlarkASTParams{
+			Program: `load("module.star", "foo", bar="baz")`,
+		},
+	}
+	res := mcptest.CallTool(t, client, params)
+	if res.IsError {
+		t.Fatalf("expected tool call to succeed, but it failed. Full result: %#v", res)
+	}
+	sc := res.StructuredContent.(map[string]any)
+	ast := sc["ast"].(map[string]any)
+	load := ast["stmts"].([]any)[0].(map[string]any)
+	if load["kind"] != "LoadStmt" || load["module"] != "module.star" {
+		t.Fatalf("expected a LoadStmt for module.star, got %#v", load)
+	}
+	bindings := load["bindings"].([]any)
+	if len(bindings) != 2 {
+		t.Fatalf("expected two bindings, got %#v", bindings)
+	}
+	aliased := bindings[1].(map[string]any)
+	if aliased["local"] != "bar" || aliased["name"] != "baz" {
+		t.Fatalf("expected bar aliased to baz, got %#v", aliased)
+	}
+}
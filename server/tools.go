@@ -4,33 +4,90 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.starlark.net/starlark"
-	"go.starlark.net/syntax"
 )
 
 const (
 	maxOutputLen        = 16 * 1024
 	executeStarlarkName = "execute-starlark"
+
+	// maxExecutionSteps bounds Starlark computation steps, as a proxy for
+	// CPU time that's independent of the wall-clock timeout: a program
+	// stuck waiting on something slow (e.g. a future http module) burns
+	// no steps and is only caught by the timeout, while a tight CPU loop
+	// hits this limit first and gets a distinct error.
+	maxExecutionSteps = 100_000_000
 )
 
 //go:embed description.md
 var executeStarlarkDescription string
 
+// docsModeEnvVar selects a shorter, auto-generated execute-starlark
+// description in place of the full prose in description.md, primarily so
+// the eval harness can measure the pass-rate/token tradeoff of sending
+// less documentation to the model. Most deployments should leave it unset.
+const docsModeEnvVar = "STARLARK_MCP_DOCS_MODE"
+
+// compactDocsMode selects compactExecuteStarlarkDescription.
+const compactDocsMode = "compact"
+
 func addExecuteStarlarkTool(server *mcp.Server) {
 	tool := &mcp.Tool{
 		Name:        executeStarlarkName,
-		Description: executeStarlarkDescription,
+		Description: executeStarlarkToolDescription(),
 	}
 	mcp.AddTool(server, tool, handleExecuteStarlarkTool)
 }
 
+// executeStarlarkToolDescription returns the full description.md prose, or
+// compactExecuteStarlarkDescription's cheat sheet if docsModeEnvVar is set
+// to compactDocsMode.
+func executeStarlarkToolDescription() string {
+	if os.Getenv(docsModeEnvVar) == compactDocsMode {
+		return compactExecuteStarlarkDescription()
+	}
+	return executeStarlarkDescription
+}
+
+// compactExecuteStarlarkDescription builds a short cheat sheet from
+// docTopics and the registered builtin modules, rather than duplicating
+// description.md's prose by hand, so it can't silently drift out of sync
+// with the per-topic docs resources it's a condensed version of.
+func compactExecuteStarlarkDescription() string {
+	var modules []string
+	for name := range builtinModules {
+		modules = append(modules, name)
+	}
+	sort.Strings(modules)
+
+	var b strings.Builder
+	b.WriteString("Executes Starlark programs (Python-like, with restrictions: ")
+	for i, t := range docTopics {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(t.description)
+	}
+	b.WriteString("). Modules available via load(): ")
+	b.WriteString(strings.Join(modules, ", "))
+	b.WriteString(". See starlark://docs for the full documentation.")
+	return b.String()
+}
+
 type executeStarlarkParams struct {
-	Program     string  `json:"program" jsonschema:"a valid Starlark program"`
-	TimeoutSecs float32 `json:"timeout_secs" jsonschema:"execution timeout in seconds"`
+	Program       string   `json:"program" jsonschema:"a valid Starlark program"`
+	TimeoutSecs   float32  `json:"timeout_secs" jsonschema:"execution timeout in seconds"`
+	Autofix       bool     `json:"autofix,omitempty" jsonschema:"mechanically rewrite common unsupported constructs (** operator, simple f-strings, top-level loops) before executing"`
+	Persist       bool     `json:"persist,omitempty" jsonschema:"save this call's top-level globals (functions, variables) and make them available to the next persist call on this session, instead of re-sending them every time"`
+	ResetSession  bool     `json:"reset_session,omitempty" jsonschema:"discard any globals persisted by earlier persist calls on this session before running"`
+	ReturnGlobals []string `json:"return_globals,omitempty" jsonschema:"names of top-level variables to JSON-encode into the result's globals field, instead of requiring the program to print() them"`
 }
 
 func (p executeStarlarkParams) validate() error {
@@ -44,37 +101,140 @@ func (p executeStarlarkParams) timeout() time.Duration {
 	return time.Duration(p.TimeoutSecs * float32(time.Second))
 }
 
+// maxStructuredStdoutLen bounds how much of the program's output is echoed
+// into executeStarlarkResult.Stdout. The full output always goes to
+// CallToolResult.Content and to read-output via OutputID; this separate,
+// smaller cap keeps the structured result itself cheap to parse even when
+// the program printed output up to maxOutputLen.
+const maxStructuredStdoutLen = readOutputPageSize
+
+// executeStarlarkResult is the structured output for execute-starlark. It
+// carries non-fatal observations alongside the program's printed output,
+// which stays in CallToolResult.Content so existing behavior is unchanged.
+type executeStarlarkResult struct {
+	Stdout         string         `json:"stdout" jsonschema:"the program's printed output, capped at a smaller size than the full text content; see truncated and output_id"`
+	Truncated      bool           `json:"truncated" jsonschema:"whether stdout was cut short of the full output; pass output_id to read-output for the rest"`
+	DurationMs     int64          `json:"duration_ms" jsonschema:"wall-clock time the program took to execute, in milliseconds"`
+	Steps          uint64         `json:"steps" jsonschema:"Starlark execution steps the program consumed, out of the maxExecutionSteps budget"`
+	Warnings       []string       `json:"warnings,omitempty" jsonschema:"non-fatal warnings about risky or deprecated constructs in the program, e.g. shadowed builtins or unused loads"`
+	OutputID       int            `json:"output_id" jsonschema:"id to pass to read-output for paginated access to this result's output"`
+	AutofixChanges []string       `json:"autofix_changes,omitempty" jsonschema:"rewrites autofix applied before executing, if autofix was requested"`
+	Globals        map[string]any `json:"globals,omitempty" jsonschema:"JSON-encoded top-level variables requested via return_globals, keyed by name"`
+}
+
+// handleExecuteStarlarkTool recovers from panics in the execution path (the
+// MCP server itself does not) and reports them as a regular tool error, so
+// one bad program can't take down the whole stdio session.
 func handleExecuteStarlarkTool(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	args executeStarlarkParams,
-) (*mcp.CallToolResult, any, error) {
+) (result *mcp.CallToolResult, out executeStarlarkResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, out, err = nil, executeStarlarkResult{}, fmt.Errorf("panic during execution: %v", r)
+		}
+	}()
+
 	if err := args.validate(); err != nil {
-		return nil, nil, err
+		return nil, executeStarlarkResult{}, err
 	}
 
 	ctx, done := context.WithTimeout(ctx, args.timeout())
 	defer done()
 
-	output, err := executeStarlark(ctx, args.Program)
+	if args.ResetSession {
+		persistedGlobals.reset(req.Session)
+	}
+
+	program := args.Program
+	var autofixChanges []string
+	if args.Autofix {
+		program, autofixChanges = autofixProgram(program)
+	}
+
+	var extra starlark.StringDict
+	if args.Persist {
+		extra = persistedGlobals.get(req.Session)
+	}
+
+	start := time.Now()
+	outcome, err := executeStarlarkWithGlobals(ctx, program, extra)
 	if err != nil {
-		return nil, nil, err
+		return nil, executeStarlarkResult{}, err
 	}
+	if args.Persist {
+		persistedGlobals.set(req.Session, outcome.globals)
+	}
+
+	var globals map[string]any
+	if len(args.ReturnGlobals) > 0 {
+		globals, err = encodeGlobals(outcome.globals, args.ReturnGlobals)
+		if err != nil {
+			return nil, executeStarlarkResult{}, err
+		}
+	}
+
+	stdout, truncated := outcome.output, false
+	if len(stdout) > maxStructuredStdoutLen {
+		stdout, truncated = stdout[:maxStructuredStdoutLen], true
+	}
+
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: output},
-		},
-	}, nil, nil
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: outcome.output},
+			},
+		}, executeStarlarkResult{
+			Stdout:         stdout,
+			Truncated:      truncated,
+			DurationMs:     time.Since(start).Milliseconds(),
+			Steps:          outcome.steps,
+			Warnings:       collectWarnings(program),
+			OutputID:       sessionOutputs.add(req.Session, outcome.output),
+			AutofixChanges: autofixChanges,
+			Globals:        globals,
+		}, nil
+}
+
+// Execute runs a Starlark program through the same engine, prelude, and
+// limits as the execute-starlark tool, and returns its output. It does not
+// apply a timeout; callers that want one should derive ctx accordingly.
+func Execute(ctx context.Context, program string) (string, error) {
+	return executeStarlark(ctx, program)
 }
 
 // executeStarlark executes the given Starlark program and returns its output.
 // The program generates output using the "print" builtin function.
 func executeStarlark(ctx context.Context, program string) (string, error) {
+	outcome, err := executeStarlarkWithGlobals(ctx, program, nil)
+	return outcome.output, err
+}
+
+// executeStarlarkOutcome is everything executeStarlarkWithGlobals learns
+// about a run that some caller, but not every caller, needs: the bare
+// output string is enough for Execute and executeStarlark, while the
+// execute-starlark tool also wants the globals, the step count, and later
+// wraps this with its own wall-clock timing.
+type executeStarlarkOutcome struct {
+	output  string
+	globals starlark.StringDict
+	steps   uint64
+}
+
+// executeStarlarkWithGlobals is executeStarlark plus the persist support
+// needed by the execute-starlark tool: it also returns the top-level globals
+// the program left behind (for a caller that wants to persist them into a
+// later call, see persistedGlobals), and accepts extra, a previous call's
+// persisted globals to merge over the usual predeclared names so execution
+// can resume where it left off.
+func executeStarlarkWithGlobals(ctx context.Context, program string, extra starlark.StringDict) (executeStarlarkOutcome, error) {
 	buf := newOutputBuffer(maxOutputLen)
+	defer buf.release()
 	thread := &starlark.Thread{
 		Print: buf.appendln,
 		Load:  loadBuiltinModule,
 	}
+	thread.SetMaxExecutionSteps(maxExecutionSteps)
 	context.AfterFunc(ctx, func() {
 		reason := ""
 		if err := ctx.Err(); err != nil {
@@ -82,23 +242,34 @@ func executeStarlark(ctx context.Context, program string) (string, error) {
 		}
 		thread.Cancel(reason)
 	})
-	opts := &syntax.FileOptions{
-		Set:             true,
-		While:           true,
-		TopLevelControl: true,
-		GlobalReassign:  true,
-		Recursion:       true,
-	}
-	_, err := starlark.ExecFileOptions(
-		opts,
+	globals, err := starlark.ExecFileOptions(
+		fileOptions(),
 		thread,
 		"LLM supplied program",
 		program,
-		predeclared())
+		withExtra(predeclared(), extra))
 	if err != nil {
-		return "", fmt.Errorf("failed to execute program: %v", err)
+		return executeStarlarkOutcome{}, fmt.Errorf("failed to execute program: %v", enrichExecutionError(err, program))
 	}
-	return buf.str(), nil
+	return executeStarlarkOutcome{output: buf.str(), globals: globals, steps: thread.ExecutionSteps()}, nil
+}
+
+// withExtra returns base with extra's bindings overlaid on top, without
+// mutating either. extra is typically a previous call's persisted globals,
+// which should shadow the predeclared names they were originally derived
+// from.
+func withExtra(base, extra starlark.StringDict) starlark.StringDict {
+	if len(extra) == 0 {
+		return base
+	}
+	merged := make(starlark.StringDict, len(base)+len(extra))
+	for name, val := range base {
+		merged[name] = val
+	}
+	for name, val := range extra {
+		merged[name] = val
+	}
+	return merged
 }
 
 type outputBuffer struct {
@@ -106,8 +277,22 @@ type outputBuffer struct {
 	buf    strings.Builder
 }
 
+// outputBufferPool reuses outputBuffers' underlying storage across
+// executions, since every call otherwise allocates and immediately
+// discards a strings.Builder.
+var outputBufferPool = sync.Pool{
+	New: func() any { return new(outputBuffer) },
+}
+
 func newOutputBuffer(maxLen int) *outputBuffer {
-	return &outputBuffer{maxLen: maxLen}
+	b := outputBufferPool.Get().(*outputBuffer)
+	b.maxLen = maxLen
+	b.buf.Reset()
+	return b
+}
+
+func (b *outputBuffer) release() {
+	outputBufferPool.Put(b)
 }
 
 func (b *outputBuffer) appendln(thread *starlark.Thread, msg string) {
@@ -121,6 +306,9 @@ func (b *outputBuffer) appendln(thread *starlark.Thread, msg string) {
 	b.buf.WriteRune('\n')
 }
 
+// str returns a copy of the accumulated output. It must copy rather than
+// alias b.buf's backing array, since the buffer is returned to a pool and
+// reused by later executions as soon as the caller releases it.
 func (b *outputBuffer) str() string {
-	return b.buf.String()
+	return strings.Clone(b.buf.String())
 }
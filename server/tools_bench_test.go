@@ -0,0 +1,64 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func benchmarkExecuteStarlark(b *testing.B, program string) {
+	ctx := b.Context()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := executeStarlark(ctx, program); err != nil {
+			b.Fatalf("executeStarlark failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkExecuteStarlark_Small(b *testing.B) {
+	benchmarkExecuteStarlark(b, `print(1 + 1)`)
+}
+
+func BenchmarkExecuteStarlark_Medium(b *testing.B) {
+	benchmarkExecuteStarlark(b, `
+def fib(n):
+    a, b = 0, 1
+    for _ in range(n):
+        a, b = b, a + b
+    return a
+
+print(fib(100))
+`)
+}
+
+func BenchmarkExecuteStarlark_Large(b *testing.B) {
+	var program strings.Builder
+	for i := range 1000 {
+		name := "x" + strconv.Itoa(i)
+		program.WriteString(name)
+		program.WriteString(" = ")
+		program.WriteString(strconv.Itoa(i))
+		program.WriteString("\n")
+	}
+	program.WriteString("print(x999)\n")
+	benchmarkExecuteStarlark(b, program.String())
+}
+
+func BenchmarkExecuteStarlark_HeavyPrintOutput(b *testing.B) {
+	benchmarkExecuteStarlark(b, `
+for i in range(500):
+    print("line %d" % i)
+`)
+}
+
+func BenchmarkExecuteStarlark_DeepDataStructure(b *testing.B) {
+	benchmarkExecuteStarlark(b, `
+d = {}
+cur = d
+for i in range(200):
+    cur["next"] = {}
+    cur = cur["next"]
+print(len(d))
+`)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// timeoutForFuzzing bounds each fuzz iteration so a hang (e.g. an
+// unbounded loop the dialect permits) fails the run instead of stalling it
+// forever.
+const timeoutForFuzzing = 2 * time.Second
+
+// FuzzExecuteStarlark feeds mutated programs into executeStarlark looking
+// for panics (e.g. the Print-callback OOM guard) and hangs. Any error
+// returned by executeStarlark is an expected outcome for malformed input;
+// only a panic or a fuzz-reported hang is a bug.
+func FuzzExecuteStarlark(f *testing.F) {
+	for _, seed := range []string{
+		``,
+		`print("hello")`,
+		`x = 1 + 1`,
+		`for i in range(10): print(i)`,
+		`def f(n): return n if n == 0 else f(n - 1)
+print(f(100))`,
+		`print("a" * 1000000)`,
+		`[][0]`,
+		`{}[1]`,
+		`1 / 0`,
+		`load("math", "sqrt")
+print(sqrt(4.0))`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, program string) {
+		ctx, cancel := context.WithTimeout(t.Context(), timeoutForFuzzing)
+		defer cancel()
+		// Errors are an expected outcome for arbitrary input; only a
+		// panic (caught by the fuzzing harness) indicates a bug.
+		_, _ = executeStarlark(ctx, program)
+	})
+}
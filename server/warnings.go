@@ -0,0 +1,141 @@
+package server
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// maxLiteralLen flags string/bytes literals and list/dict/set display
+// literals longer than this many elements as "huge", since they tend to
+// be the result of an LLM pasting generated data rather than something a
+// human wrote by hand, and they eat into the output/step budgets fast.
+const maxLiteralLen = 1000
+
+// collectWarnings re-parses program and returns non-fatal observations
+// about risky or deprecated constructs: shadowing a builtin, loading a
+// name that's never used, and suspiciously huge literals. It never
+// returns an error for programs that executeStarlark itself accepted;
+// a parse failure here just means no warnings are reported.
+func collectWarnings(program string) []string {
+	f, err := fileOptions().Parse("LLM supplied program", program, 0)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []string
+	warnings = append(warnings, shadowedBuiltinWarnings(f)...)
+	warnings = append(warnings, unusedLoadWarnings(f)...)
+	warnings = append(warnings, hugeLiteralWarnings(f)...)
+	return warnings
+}
+
+// shadowedBuiltinWarnings reports assignments and function definitions
+// that reuse the name of a predeclared builtin or prelude helper,
+// shadowing it for the rest of the program.
+func shadowedBuiltinWarnings(f *syntax.File) []string {
+	var warnings []string
+	seen := make(map[string]bool)
+	report := func(name string, pos syntax.Position) {
+		if seen[name] {
+			return
+		}
+		if _, ok := starlark.Universe[name]; !ok {
+			if _, ok := predeclared()[name]; !ok {
+				return
+			}
+		}
+		seen[name] = true
+		warnings = append(warnings, fmt.Sprintf("%s: %q shadows a builtin", pos, name))
+	}
+
+	syntax.Walk(f, func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.DefStmt:
+			report(n.Name.Name, n.Name.NamePos)
+		case *syntax.AssignStmt:
+			if n.Op == syntax.EQ {
+				for _, id := range assignedIdents(n.LHS) {
+					report(id.Name, id.NamePos)
+				}
+			}
+		}
+		return true
+	})
+	return warnings
+}
+
+// assignedIdents returns the identifiers bound by an assignment's LHS,
+// which may be a single name, a tuple/list of names, or (for += etc.)
+// something other than an Ident that binds nothing new.
+func assignedIdents(lhs syntax.Expr) []*syntax.Ident {
+	switch lhs := lhs.(type) {
+	case *syntax.Ident:
+		return []*syntax.Ident{lhs}
+	case *syntax.TupleExpr:
+		var idents []*syntax.Ident
+		for _, e := range lhs.List {
+			idents = append(idents, assignedIdents(e)...)
+		}
+		return idents
+	case *syntax.ListExpr:
+		var idents []*syntax.Ident
+		for _, e := range lhs.List {
+			idents = append(idents, assignedIdents(e)...)
+		}
+		return idents
+	case *syntax.ParenExpr:
+		return assignedIdents(lhs.X)
+	default:
+		return nil
+	}
+}
+
+// unusedLoadWarnings reports names bound by a load() statement that are
+// never referenced anywhere else in the file.
+func unusedLoadWarnings(f *syntax.File) []string {
+	var loaded []*syntax.Ident
+	refs := make(map[string]int)
+
+	syntax.Walk(f, func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.LoadStmt:
+			loaded = append(loaded, n.From...)
+			return false
+		case *syntax.Ident:
+			refs[n.Name]++
+		}
+		return true
+	})
+
+	var warnings []string
+	for _, id := range loaded {
+		if refs[id.Name] == 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: loaded name %q is never used", id.NamePos, id.Name))
+		}
+	}
+	return warnings
+}
+
+// hugeLiteralWarnings reports list, dict, and set display literals with
+// more than maxLiteralLen elements.
+func hugeLiteralWarnings(f *syntax.File) []string {
+	var warnings []string
+	syntax.Walk(f, func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.ListExpr:
+			if len(n.List) > maxLiteralLen {
+				start, _ := n.Span()
+				warnings = append(warnings, fmt.Sprintf("%s: huge literal detected (%d elements)", start, len(n.List)))
+			}
+		case *syntax.DictExpr:
+			if len(n.List) > maxLiteralLen {
+				start, _ := n.Span()
+				warnings = append(warnings, fmt.Sprintf("%s: huge literal detected (%d elements)", start, len(n.List)))
+			}
+		}
+		return true
+	})
+	return warnings
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollectWarnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		want    string // substring expected somewhere in the warnings, or "" for none
+	}{
+		{
+			name:    "shadowed builtin",
+			program: `len = 5`,
+			want:    `"len" shadows a builtin`,
+		},
+		{
+			name:    "shadowed builtin via def",
+			program: "def print():\n    pass",
+			want:    `"print" shadows a builtin`,
+		},
+		{
+			name:    "unused load",
+			program: `load("math", "sqrt")`,
+			want:    `loaded name "sqrt" is never used`,
+		},
+		{
+			name:    "used load",
+			program: "load(\"math\", \"sqrt\")\nprint(sqrt(4.0))",
+			want:    "",
+		},
+		{
+			name:    "huge literal",
+			program: listLiteral(maxLiteralLen + 1),
+			want:    "huge literal detected",
+		},
+		{
+			name:    "small literal",
+			program: listLiteral(3),
+			want:    "",
+		},
+		{
+			name:    "unshadowed assignment",
+			program: `x = 5`,
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := collectWarnings(tt.program)
+			if tt.want == "" {
+				if len(warnings) != 0 {
+					t.Fatalf("got warnings %v, want none", warnings)
+				}
+				return
+			}
+			for _, w := range warnings {
+				if strings.Contains(w, tt.want) {
+					return
+				}
+			}
+			t.Fatalf("got warnings %v, want one containing %q", warnings, tt.want)
+		})
+	}
+}
+
+func listLiteral(n int) string {
+	elems := make([]string, n)
+	for i := range elems {
+		elems[i] = "0"
+	}
+	return "x = [" + strings.Join(elems, ", ") + "]"
+}